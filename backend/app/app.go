@@ -10,9 +10,12 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,23 +31,41 @@ import (
 )
 
 type AppService struct {
-	DB           *database.DB
-	YouTube      *services.YouTubeService
-	Transcript   *services.TranscriptService
-	LLM          *services.LLMService
-	Template     *services.TemplateService
-	Notification *services.NotificationService
-	Tagging      *services.TaggingService
-	Export       *services.ExportService
+	DB                 *database.DB
+	YouTube            *services.YouTubeService
+	YouTubeData        *services.YouTubeDataService
+	Transcript         *services.TranscriptService
+	Subtitles          *services.YTDLPSubtitleProvider
+	LLM                *services.LLMService
+	Template           *services.TemplateService
+	Notification       *services.NotificationService
+	Tagging            *services.TaggingService
+	Export             *services.ExportService
+	OPML               *services.OPMLService
+	SubscriptionImport *services.SubscriptionImportService
+	WebSub             *services.WebSubService
+	YTDLP              *services.YTDLPService
+	Thumbnails         *services.ThumbnailCache
+	FeedSources        map[string]services.FeedSource
 
 	syncMu       sync.RWMutex
 	syncSettings SyncSettings
+	syncWriteMu  sync.Mutex
 
 	dbPath string
 	logger *log.Logger
 
 	summaryMu      sync.Mutex
 	summaryRunning bool
+
+	transcriptBreaker transcriptBreaker
+
+	importMu       sync.Mutex
+	importProgress ImportProgress
+
+	webSubMu     sync.Mutex
+	webSubServer *http.Server
+	webSubAddr   string
 }
 
 type SyncResult struct {
@@ -52,54 +73,109 @@ type SyncResult struct {
 	ChannelName   string
 	NewVideos     int
 	UpdatedVideos int
+	RemovedVideos int
+	Unchanged     bool
+	Muted         bool
+	Error         string
 }
 
 type SyncSettings struct {
-	Enabled              bool
-	IntervalMinutes      int
-	NotificationsEnabled bool
+	Enabled               bool
+	IntervalMinutes       int
+	NotificationsEnabled  bool
+	Concurrency           int
+	ChannelTimeoutSeconds int
 }
 
 type SyncSettingsInput struct {
-	Enabled              bool
-	IntervalMinutes      int
-	NotificationsEnabled bool
+	Enabled               bool
+	IntervalMinutes       int
+	NotificationsEnabled  bool
+	Concurrency           int
+	ChannelTimeoutSeconds int
 }
 
 type SyncSummary struct {
-	TotalNew     int
-	TotalUpdated int
-	Channels     []SyncResult
+	TotalNew       int
+	TotalUpdated   int
+	TotalUnchanged int
+	TotalFailed    int
+	Channels       []SyncResult
+	Playlists      []PlaylistSyncResult
 }
 
 type AppSettings struct {
-	LLMProvider            string
-	OpenAIKey              string
-	OpenAIModel            string
-	OllamaURL              string
-	ResponseLanguage       string
-	SelectedTemplate       string
-	AutoSyncEnabled        bool
-	SyncIntervalMinutes    int
-	NotificationsEnabled   bool
-	AutoSummaryEnabled     bool
-	SummaryIntervalMinutes int
-	SummaryBatchSize       int
+	LLMProvider               string
+	OpenAIKey                 string
+	OpenAIModel               string
+	OllamaURL                 string
+	YouTubeAPIKey             string
+	YouTubeAPIBaseURL         string
+	WebSubEnabled             bool
+	WebSubHubURL              string
+	WebSubCallbackURL         string
+	WebSubListenAddr          string
+	YTDLPEnabled              bool
+	YTDLPPath                 string
+	ThumbnailCacheEnabled     bool
+	ThumbnailCacheMaxMB       int
+	TranscriptLanguages       string
+	TranscriptTrack           string
+	TranscriptYTDLPEnabled    bool
+	ASREnabled                bool
+	ASRExtractCommand         string
+	ASRWhisperPath            string
+	ASRModelPath              string
+	ASRLanguage               string
+	ASRMaxDurationMinutes     int
+	ASRConcurrency            int
+	ResponseLanguage          string
+	SelectedTemplate          string
+	AutoSyncEnabled           bool
+	SyncIntervalMinutes       int
+	SyncConcurrency           int
+	SyncChannelTimeoutSeconds int
+	NotificationsEnabled      bool
+	AutoSummaryEnabled        bool
+	SummaryIntervalMinutes    int
+	SummaryBatchSize          int
 }
 
 type AppSettingsInput struct {
-	LLMProvider            string
-	OpenAIKey              string
-	OpenAIModel            string
-	OllamaURL              string
-	ResponseLanguage       string
-	SelectedTemplate       string
-	AutoSyncEnabled        bool
-	SyncIntervalMinutes    int
-	NotificationsEnabled   bool
-	AutoSummaryEnabled     bool
-	SummaryIntervalMinutes int
-	SummaryBatchSize       int
+	LLMProvider               string
+	OpenAIKey                 string
+	OpenAIModel               string
+	OllamaURL                 string
+	YouTubeAPIKey             string
+	YouTubeAPIBaseURL         string
+	WebSubEnabled             bool
+	WebSubHubURL              string
+	WebSubCallbackURL         string
+	WebSubListenAddr          string
+	YTDLPEnabled              bool
+	YTDLPPath                 string
+	ThumbnailCacheEnabled     bool
+	ThumbnailCacheMaxMB       int
+	TranscriptLanguages       string
+	TranscriptTrack           string
+	TranscriptYTDLPEnabled    bool
+	ASREnabled                bool
+	ASRExtractCommand         string
+	ASRWhisperPath            string
+	ASRModelPath              string
+	ASRLanguage               string
+	ASRMaxDurationMinutes     int
+	ASRConcurrency            int
+	ResponseLanguage          string
+	SelectedTemplate          string
+	AutoSyncEnabled           bool
+	SyncIntervalMinutes       int
+	SyncConcurrency           int
+	SyncChannelTimeoutSeconds int
+	NotificationsEnabled      bool
+	AutoSummaryEnabled        bool
+	SummaryIntervalMinutes    int
+	SummaryBatchSize          int
 }
 
 type TemplateInput struct {
@@ -124,6 +200,8 @@ type SummarizeRequest struct {
 	Channel   string
 	Duration  string
 	KeyPoints string
+	Category  string
+	Language  string
 }
 
 type VideoItem struct {
@@ -134,18 +212,43 @@ type VideoItem struct {
 	ChannelID             uint
 	ChannelName           string
 	Thumbnail             string
+	Description           string
+	MediaURL              string
+	MediaType             string
+	SourceType            string
+	VideoType             string
+	DurationSeconds       int
+	Category              string
+	Language              string
+	Muted                 bool
+	ViewCount             int64
+	RatingAverage         float64
 	Summary               string
 	Transcript            string
 	TranscriptStatus      string
 	TranscriptLastError   string
 	TranscriptLastAttempt *time.Time
-	PublishedAt           time.Time
+	TranscriptAttempts    int
+	TranscriptNextAttempt *time.Time
+	// TranscriptLanguageUsed, TranscriptTrackUsed and TranscriptSource
+	// describe where the stored transcript came from.
+	TranscriptLanguageUsed string
+	TranscriptTrackUsed    string
+	TranscriptSource       string
+	PublishedAt            time.Time
+	Removed                bool
+	RemovedAt              *time.Time
 }
 
 type VideoFilter struct {
 	ChannelID string
+	GroupID   uint
 	TagID     uint
 	Query     string
+	Sort      string
+	VideoType string
+	// IncludeMuted also returns videos hidden by a rule or a muted channel.
+	IncludeMuted bool
 }
 
 type CollectionInput struct {
@@ -193,22 +296,39 @@ func NewAppService(dbPath string) (*AppService, error) {
 	}
 
 	appService := &AppService{
-		DB:           db,
-		YouTube:      &services.YouTubeService{},
-		Transcript:   &services.TranscriptService{},
-		LLM:          &services.LLMService{},
-		Template:     &services.TemplateService{},
-		Notification: &services.NotificationService{},
-		Tagging:      &services.TaggingService{},
-		Export:       &services.ExportService{},
+		DB:                 db,
+		YouTube:            &services.YouTubeService{},
+		YouTubeData:        &services.YouTubeDataService{},
+		Transcript:         &services.TranscriptService{},
+		Subtitles:          &services.YTDLPSubtitleProvider{},
+		LLM:                &services.LLMService{},
+		Template:           &services.TemplateService{},
+		Notification:       &services.NotificationService{},
+		Tagging:            &services.TaggingService{},
+		Export:             &services.ExportService{},
+		OPML:               &services.OPMLService{},
+		SubscriptionImport: &services.SubscriptionImportService{},
+		WebSub:             &services.WebSubService{},
+		YTDLP:              &services.YTDLPService{},
+		Thumbnails:         &services.ThumbnailCache{Dir: filepath.Join(filepath.Dir(dbPath), "thumbs")},
 		syncSettings: SyncSettings{
-			Enabled:              true,
-			IntervalMinutes:      30,
-			NotificationsEnabled: true,
+			Enabled:               true,
+			IntervalMinutes:       30,
+			NotificationsEnabled:  true,
+			Concurrency:           4,
+			ChannelTimeoutSeconds: 30,
 		},
 		dbPath: dbPath,
 		logger: newAppLogger(),
 	}
+	appService.FeedSources = map[string]services.FeedSource{
+		services.SourceYouTube: appService.YouTube,
+		services.SourceRSS:     &services.RSSFeedService{},
+	}
+	appService.Transcript.Providers = []services.TranscriptProvider{
+		&services.CaptionAPIProvider{},
+		appService.Subtitles,
+	}
 
 	if err := appService.SeedDefaultTemplates(); err != nil {
 		return nil, fmt.Errorf("seed templates: %w", err)
@@ -216,10 +336,16 @@ func NewAppService(dbPath string) (*AppService, error) {
 
 	if settings, err := appService.GetAppSettings(); err == nil {
 		_, _ = appService.UpdateSyncSettings(SyncSettingsInput{
-			Enabled:              settings.AutoSyncEnabled,
-			IntervalMinutes:      settings.SyncIntervalMinutes,
-			NotificationsEnabled: settings.NotificationsEnabled,
+			Enabled:               settings.AutoSyncEnabled,
+			IntervalMinutes:       settings.SyncIntervalMinutes,
+			NotificationsEnabled:  settings.NotificationsEnabled,
+			Concurrency:           settings.SyncConcurrency,
+			ChannelTimeoutSeconds: settings.SyncChannelTimeoutSeconds,
 		})
+		if err := appService.Thumbnails.SetMaxBytes(int64(settings.ThumbnailCacheMaxMB) << 20); err != nil && appService.logger != nil {
+			appService.logger.Printf("evict thumbnails failed: %v", err)
+		}
+		appService.Subtitles.Configure(settings.TranscriptYTDLPEnabled, settings.YTDLPPath)
 	}
 
 	return appService, nil
@@ -243,17 +369,38 @@ func (a *AppService) LogClientError(message string) {
 
 func (a *AppService) GetAppSettings() (AppSettings, error) {
 	settings := AppSettings{
-		LLMProvider:            getSetting(a.DB, "llm_provider", "ollama"),
-		OpenAIModel:            getSetting(a.DB, "openai_model", "gpt-4o-mini"),
-		OllamaURL:              getSetting(a.DB, "ollama_url", "http://localhost:11434"),
-		ResponseLanguage:       getSetting(a.DB, "response_language", "ko"),
-		SelectedTemplate:       getSetting(a.DB, "selected_template", ""),
-		AutoSyncEnabled:        getSettingBool(a.DB, "auto_sync_enabled", true),
-		SyncIntervalMinutes:    getSettingInt(a.DB, "sync_interval_minutes", 30),
-		NotificationsEnabled:   getSettingBool(a.DB, "notifications_enabled", true),
-		AutoSummaryEnabled:     getSettingBool(a.DB, "auto_summary_enabled", false),
-		SummaryIntervalMinutes: getSettingInt(a.DB, "summary_interval_minutes", 60),
-		SummaryBatchSize:       getSettingInt(a.DB, "summary_batch_size", 3),
+		LLMProvider:               getSetting(a.DB, "llm_provider", "ollama"),
+		OpenAIModel:               getSetting(a.DB, "openai_model", "gpt-4o-mini"),
+		OllamaURL:                 getSetting(a.DB, "ollama_url", "http://localhost:11434"),
+		YouTubeAPIBaseURL:         getSetting(a.DB, "youtube_api_base_url", ""),
+		WebSubEnabled:             getSettingBool(a.DB, "websub_enabled", false),
+		WebSubHubURL:              getSetting(a.DB, "websub_hub_url", services.DefaultWebSubHubURL),
+		WebSubCallbackURL:         getSetting(a.DB, "websub_callback_url", ""),
+		WebSubListenAddr:          getSetting(a.DB, "websub_listen_addr", defaultWebSubListenAddr),
+		YTDLPEnabled:              getSettingBool(a.DB, "ytdlp_enabled", false),
+		YTDLPPath:                 getSetting(a.DB, "ytdlp_path", ""),
+		ThumbnailCacheEnabled:     getSettingBool(a.DB, "thumbnail_cache_enabled", true),
+		ThumbnailCacheMaxMB:       getSettingInt(a.DB, "thumbnail_cache_max_mb", 200),
+		TranscriptLanguages:       getSetting(a.DB, "transcript_languages", defaultTranscriptLanguages),
+		TranscriptTrack:           getSetting(a.DB, "transcript_track", services.TranscriptTrackPreferManual),
+		TranscriptYTDLPEnabled:    getSettingBool(a.DB, "transcript_ytdlp_enabled", false),
+		ASREnabled:                getSettingBool(a.DB, "asr_enabled", false),
+		ASRExtractCommand:         getSetting(a.DB, "asr_extract_command", services.DefaultASRExtractCommand),
+		ASRWhisperPath:            getSetting(a.DB, "asr_whisper_path", ""),
+		ASRModelPath:              getSetting(a.DB, "asr_model_path", ""),
+		ASRLanguage:               getSetting(a.DB, "asr_language", "auto"),
+		ASRMaxDurationMinutes:     getSettingInt(a.DB, "asr_max_duration_minutes", 60),
+		ASRConcurrency:            getSettingInt(a.DB, "asr_concurrency", 1),
+		ResponseLanguage:          getSetting(a.DB, "response_language", "ko"),
+		SelectedTemplate:          getSetting(a.DB, "selected_template", ""),
+		AutoSyncEnabled:           getSettingBool(a.DB, "auto_sync_enabled", true),
+		SyncIntervalMinutes:       getSettingInt(a.DB, "sync_interval_minutes", 30),
+		SyncConcurrency:           getSettingInt(a.DB, "sync_concurrency", 4),
+		SyncChannelTimeoutSeconds: getSettingInt(a.DB, "sync_channel_timeout_seconds", 30),
+		NotificationsEnabled:      getSettingBool(a.DB, "notifications_enabled", true),
+		AutoSummaryEnabled:        getSettingBool(a.DB, "auto_summary_enabled", false),
+		SummaryIntervalMinutes:    getSettingInt(a.DB, "summary_interval_minutes", 60),
+		SummaryBatchSize:          getSettingInt(a.DB, "summary_batch_size", 3),
 	}
 	enc := getSetting(a.DB, "openai_key", "")
 	if enc != "" {
@@ -261,6 +408,11 @@ func (a *AppService) GetAppSettings() (AppSettings, error) {
 			settings.OpenAIKey = dec
 		}
 	}
+	if enc := getSetting(a.DB, "youtube_api_key", ""); enc != "" {
+		if dec, err := decryptString(enc); err == nil {
+			settings.YouTubeAPIKey = dec
+		}
+	}
 	return settings, nil
 }
 
@@ -268,6 +420,43 @@ func (a *AppService) SaveAppSettings(input AppSettingsInput) (AppSettings, error
 	setSetting(a.DB, "llm_provider", input.LLMProvider)
 	setSetting(a.DB, "openai_model", input.OpenAIModel)
 	setSetting(a.DB, "ollama_url", input.OllamaURL)
+	setSetting(a.DB, "youtube_api_base_url", input.YouTubeAPIBaseURL)
+	setSetting(a.DB, "websub_enabled", fmt.Sprintf("%t", input.WebSubEnabled))
+	setSetting(a.DB, "websub_hub_url", input.WebSubHubURL)
+	setSetting(a.DB, "websub_callback_url", input.WebSubCallbackURL)
+	setSetting(a.DB, "websub_listen_addr", input.WebSubListenAddr)
+	setSetting(a.DB, "ytdlp_enabled", fmt.Sprintf("%t", input.YTDLPEnabled))
+	setSetting(a.DB, "ytdlp_path", input.YTDLPPath)
+	setSetting(a.DB, "thumbnail_cache_enabled", fmt.Sprintf("%t", input.ThumbnailCacheEnabled))
+	if input.ThumbnailCacheMaxMB > 0 {
+		setSetting(a.DB, "thumbnail_cache_max_mb", fmt.Sprintf("%d", input.ThumbnailCacheMaxMB))
+		if err := a.Thumbnails.SetMaxBytes(int64(input.ThumbnailCacheMaxMB) << 20); err != nil && a.logger != nil {
+			a.logger.Printf("evict thumbnails failed: %v", err)
+		}
+	}
+	if languages := normalizeLanguages(strings.Split(input.TranscriptLanguages, ",")); len(languages) > 0 {
+		setSetting(a.DB, "transcript_languages", strings.Join(languages, ","))
+	}
+	if services.IsValidTranscriptTrack(input.TranscriptTrack) {
+		setSetting(a.DB, "transcript_track", input.TranscriptTrack)
+	}
+	setSetting(a.DB, "transcript_ytdlp_enabled", fmt.Sprintf("%t", input.TranscriptYTDLPEnabled))
+	a.Subtitles.Configure(input.TranscriptYTDLPEnabled, input.YTDLPPath)
+	setSetting(a.DB, "asr_enabled", fmt.Sprintf("%t", input.ASREnabled))
+	if strings.TrimSpace(input.ASRExtractCommand) != "" {
+		setSetting(a.DB, "asr_extract_command", input.ASRExtractCommand)
+	}
+	setSetting(a.DB, "asr_whisper_path", input.ASRWhisperPath)
+	setSetting(a.DB, "asr_model_path", input.ASRModelPath)
+	if strings.TrimSpace(input.ASRLanguage) != "" {
+		setSetting(a.DB, "asr_language", input.ASRLanguage)
+	}
+	if input.ASRMaxDurationMinutes > 0 {
+		setSetting(a.DB, "asr_max_duration_minutes", fmt.Sprintf("%d", input.ASRMaxDurationMinutes))
+	}
+	if input.ASRConcurrency > 0 {
+		setSetting(a.DB, "asr_concurrency", fmt.Sprintf("%d", input.ASRConcurrency))
+	}
 	if strings.TrimSpace(input.ResponseLanguage) != "" {
 		setSetting(a.DB, "response_language", input.ResponseLanguage)
 	}
@@ -276,6 +465,12 @@ func (a *AppService) SaveAppSettings(input AppSettingsInput) (AppSettings, error
 	}
 	setSetting(a.DB, "auto_sync_enabled", fmt.Sprintf("%t", input.AutoSyncEnabled))
 	setSetting(a.DB, "sync_interval_minutes", fmt.Sprintf("%d", input.SyncIntervalMinutes))
+	if input.SyncConcurrency > 0 {
+		setSetting(a.DB, "sync_concurrency", fmt.Sprintf("%d", input.SyncConcurrency))
+	}
+	if input.SyncChannelTimeoutSeconds > 0 {
+		setSetting(a.DB, "sync_channel_timeout_seconds", fmt.Sprintf("%d", input.SyncChannelTimeoutSeconds))
+	}
 	setSetting(a.DB, "notifications_enabled", fmt.Sprintf("%t", input.NotificationsEnabled))
 	setSetting(a.DB, "auto_summary_enabled", fmt.Sprintf("%t", input.AutoSummaryEnabled))
 	if input.SummaryIntervalMinutes > 0 {
@@ -289,11 +484,21 @@ func (a *AppService) SaveAppSettings(input AppSettingsInput) (AppSettings, error
 			setSetting(a.DB, "openai_key", enc)
 		}
 	}
+	if strings.TrimSpace(input.YouTubeAPIKey) != "" {
+		if enc, err := encryptString(input.YouTubeAPIKey); err == nil {
+			setSetting(a.DB, "youtube_api_key", enc)
+		}
+	}
 	_, _ = a.UpdateSyncSettings(SyncSettingsInput{
-		Enabled:              input.AutoSyncEnabled,
-		IntervalMinutes:      input.SyncIntervalMinutes,
-		NotificationsEnabled: input.NotificationsEnabled,
+		Enabled:               input.AutoSyncEnabled,
+		IntervalMinutes:       input.SyncIntervalMinutes,
+		NotificationsEnabled:  input.NotificationsEnabled,
+		Concurrency:           input.SyncConcurrency,
+		ChannelTimeoutSeconds: input.SyncChannelTimeoutSeconds,
 	})
+	if err := a.StartWebSub(); err != nil && a.logger != nil {
+		a.logger.Printf("websub restart failed: %v", err)
+	}
 	return a.GetAppSettings()
 }
 
@@ -311,10 +516,26 @@ func (a *AppService) UpdateSyncSettings(input SyncSettingsInput) (SyncSettings,
 	if interval > 1440 {
 		interval = 1440
 	}
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	if concurrency > 16 {
+		concurrency = 16
+	}
+	timeout := input.ChannelTimeoutSeconds
+	if timeout <= 0 {
+		timeout = 30
+	}
+	if timeout > 600 {
+		timeout = 600
+	}
 	settings := SyncSettings{
-		Enabled:              input.Enabled,
-		IntervalMinutes:      interval,
-		NotificationsEnabled: input.NotificationsEnabled,
+		Enabled:               input.Enabled,
+		IntervalMinutes:       interval,
+		NotificationsEnabled:  input.NotificationsEnabled,
+		Concurrency:           concurrency,
+		ChannelTimeoutSeconds: timeout,
 	}
 	a.syncMu.Lock()
 	a.syncSettings = settings
@@ -330,6 +551,44 @@ func (a *AppService) ListChannels() ([]models.Channel, error) {
 	return channels, nil
 }
 
+// SetChannelPaused stops or resumes syncing a channel. Its videos and
+// summaries are kept.
+func (a *AppService) SetChannelPaused(channelID string, paused bool) error {
+	if strings.TrimSpace(channelID) == "" {
+		return fmt.Errorf("channelID is required")
+	}
+	return a.DB.Gorm.Model(&models.Channel{}).Where("channel_id = ?", channelID).Update("paused", paused).Error
+}
+
+// SetChannelMuted keeps a channel syncing but hides its videos from the
+// default video list, notifications and auto-summary.
+func (a *AppService) SetChannelMuted(channelID string, muted bool) error {
+	if strings.TrimSpace(channelID) == "" {
+		return fmt.Errorf("channelID is required")
+	}
+	return a.DB.Gorm.Model(&models.Channel{}).Where("channel_id = ?", channelID).Update("muted", muted).Error
+}
+
+func (a *AppService) SetChannelSummaryExcludeTypes(channelID string, videoTypes []string) error {
+	if strings.TrimSpace(channelID) == "" {
+		return fmt.Errorf("channelID is required")
+	}
+	types := make([]string, 0, len(videoTypes))
+	for _, t := range videoTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if !services.IsValidVideoType(t) {
+			return fmt.Errorf("unknown video type: %s", t)
+		}
+		types = append(types, t)
+	}
+	return a.DB.Gorm.Model(&models.Channel{}).
+		Where("channel_id = ?", channelID).
+		Update("summary_exclude_types", strings.Join(types, ",")).Error
+}
+
 func (a *AppService) DeleteChannel(channelID string) error {
 	if strings.TrimSpace(channelID) == "" {
 		return fmt.Errorf("channelID is required")
@@ -341,10 +600,59 @@ func (a *AppService) DeleteChannel(channelID string) error {
 	}
 
 	// Remove channel and associated videos
-	if err := a.DB.Gorm.Where("channel_id = ?", channel.ID).Delete(&models.Video{}).Error; err != nil {
+	var videos []models.Video
+	if err := a.DB.Gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id", "video_id").Where("channel_id = ?", channel.ID).Find(&videos).Error; err != nil {
+			return err
+		}
+		if err := deleteVideos(tx, videos); err != nil {
+			return err
+		}
+		if err := tx.Where("channel_id = ?", channel.ID).Delete(&models.ChannelGroupMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&channel).Error
+	}); err != nil {
 		return err
 	}
-	return a.DB.Gorm.Delete(&channel).Error
+	a.removeThumbnails(videos)
+	return nil
+}
+
+// deleteVideos deletes videos along with every row that refers to them:
+// transcript segments, stats, revisions, and tag, collection and playlist
+// links. Only the row IDs of videos are used.
+func deleteVideos(tx *gorm.DB, videos []models.Video) error {
+	ids := make([]uint, 0, len(videos))
+	for _, video := range videos {
+		ids = append(ids, video.ID)
+	}
+	const chunk = 500
+	for start := 0; start < len(ids); start += chunk {
+		end := start + chunk
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := ids[start:end]
+		for _, model := range []interface{}{
+			&models.TranscriptSegment{},
+			&models.VideoStat{},
+			&models.VideoRevision{},
+			&models.CollectionVideo{},
+			&models.PlaylistVideo{},
+		} {
+			if err := tx.Where("video_id IN ?", batch).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM video_tags WHERE video_id IN ?", batch).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN ?", batch).Delete(&models.Video{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (a *AppService) ListVideos(limit, offset int, filter VideoFilter) ([]VideoItem, error) {
@@ -360,13 +668,30 @@ func (a *AppService) ListVideos(limit, offset int, filter VideoFilter) ([]VideoI
 
 	var videos []VideoItem
 	dbQuery := a.DB.Gorm.Table("videos").
-		Select("distinct videos.id, videos.video_id, videos.title, videos.url, videos.channel_id, channels.name as channel_name, videos.thumbnail, videos.summary, videos.transcript, videos.transcript_status, videos.transcript_last_error, videos.transcript_last_attempt, videos.published_at").
+		Select("distinct videos.id, videos.video_id, videos.title, videos.url, videos.channel_id, channels.name as channel_name, videos.thumbnail, videos.description, videos.media_url, videos.media_type, channels.source_type, videos.video_type, videos.duration_seconds, videos.category, videos.language, videos.muted, videos.view_count, videos.rating_average, videos.summary, videos.transcript, videos.transcript_status, videos.transcript_last_error, videos.transcript_last_attempt, videos.transcript_attempts, videos.transcript_next_attempt, videos.transcript_language_used, videos.transcript_track_used, videos.transcript_source, videos.published_at, videos.removed, videos.removed_at").
 		Joins("left join channels on channels.id = videos.channel_id")
 
 	if channelID != "" {
 		dbQuery = dbQuery.Where("channels.channel_id = ?", channelID)
 	}
 
+	if !filter.IncludeMuted {
+		dbQuery = dbQuery.Where("videos.muted = ?", false)
+		// Picking a muted channel explicitly still lists its videos.
+		if channelID == "" {
+			dbQuery = dbQuery.Where("(channels.muted IS NULL OR channels.muted = ?)", false)
+		}
+	}
+
+	if filter.GroupID != 0 {
+		dbQuery = dbQuery.Joins("inner join channel_group_members on channel_group_members.channel_id = videos.channel_id").
+			Where("channel_group_members.channel_group_id = ?", filter.GroupID)
+	}
+
+	if videoType := strings.ToLower(strings.TrimSpace(filter.VideoType)); videoType != "" {
+		dbQuery = dbQuery.Where("videos.video_type = ?", videoType)
+	}
+
 	if filter.TagID != 0 {
 		dbQuery = dbQuery.Joins("inner join video_tags on video_tags.video_id = videos.id").
 			Where("video_tags.tag_id = ?", filter.TagID)
@@ -380,14 +705,25 @@ func (a *AppService) ListVideos(limit, offset int, filter VideoFilter) ([]VideoI
 		)
 	}
 
+	order := "videos.published_at desc"
+	switch strings.ToLower(strings.TrimSpace(filter.Sort)) {
+	case "popular", "views":
+		order = "videos.view_count desc, videos.published_at desc"
+	case "rating":
+		order = "videos.rating_average desc, videos.rating_count desc, videos.published_at desc"
+	case "oldest":
+		order = "videos.published_at asc"
+	}
+
 	if err := dbQuery.
-		Order("videos.published_at desc").
+		Order(order).
 		Limit(limit).
 		Offset(offset).
 		Scan(&videos).Error; err != nil {
 		return nil, err
 	}
 
+	a.localThumbnails(videos)
 	return videos, nil
 }
 
@@ -565,6 +901,7 @@ func (a *AppService) ListCollectionVideos(collectionID uint) ([]VideoItem, error
 		Scan(&videos).Error; err != nil {
 		return nil, err
 	}
+	a.localThumbnails(videos)
 	return videos, nil
 }
 
@@ -818,13 +1155,20 @@ func (a *AppService) AutoTagVideo(videoID string, provider string, model string,
 	if text == "" {
 		text = strings.TrimSpace(video.Transcript)
 	}
-	if text == "" {
-		transcript, err := a.Transcript.FetchTranscript(context.Background(), video.VideoID, []string{"ko", "en"})
-		if err == nil && strings.TrimSpace(transcript) != "" {
-			text = transcript
-			_ = a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Update("transcript", transcript).Error
+	if text == "" && video.TranscriptStatus != transcriptStatusGaveUp {
+		// Tags can make do with the description, so a video in cooldown is
+		// not fetched again and a failure only advances its schedule.
+		var channel models.Channel
+		_ = a.DB.Gorm.Select("source_type").First(&channel, video.ChannelID).Error
+		if isYouTubeChannel(channel) {
+			if transcript, err := a.fetchScheduledTranscript(context.Background(), video); err == nil {
+				text = transcript.Text
+			}
 		}
 	}
+	if strings.TrimSpace(text) == "" {
+		text = strings.TrimSpace(video.Description)
+	}
 	if strings.TrimSpace(text) == "" {
 		return AutoTagResult{}, fmt.Errorf("no text available to tag")
 	}
@@ -981,41 +1325,38 @@ func (a *AppService) SummarizeVideo(videoID string, templateName string, provide
 	if err := a.DB.Gorm.Where("id = ?", video.ChannelID).First(&channel).Error; err != nil {
 		return "", err
 	}
+	// Enriched first so a template picked by a duration rule applies.
+	if video.EnrichedAt == nil && isYouTubeChannel(channel) {
+		if enriched, err := a.enrichStoredVideo(context.Background(), video); err == nil {
+			video = enriched
+		} else if !errors.Is(err, errEnrichmentDisabled) && a.logger != nil {
+			a.logger.Printf("enrich video failed: %s: %v", video.VideoID, err)
+		}
+	}
+	if strings.TrimSpace(templateName) == "" {
+		templateName = video.TemplateName
+	}
+	if strings.TrimSpace(templateName) == "" {
+		templateName = a.groupTemplateName(channel.ID)
+	}
 
 	text := video.Transcript
-	if strings.TrimSpace(text) == "" {
-		if skip, wait := shouldSkipTranscriptFetch(video); skip {
-			waitMinutes := int(wait.Round(time.Minute).Minutes())
-			if waitMinutes < 1 {
-				waitMinutes = 1
-			}
-			return "", fmt.Errorf("transcript retry cooldown active (%d minutes remaining)", waitMinutes)
-		}
-		now := time.Now()
-		_ = a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Updates(map[string]interface{}{
-			"transcript_last_attempt": &now,
-		}).Error
-		transcript, err := a.Transcript.FetchTranscript(context.Background(), video.VideoID, []string{"ko", "en"})
-		if err == nil && strings.TrimSpace(transcript) != "" {
-			text = transcript
-			_ = a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Updates(map[string]interface{}{
-				"transcript":              transcript,
-				"transcript_status":       "ok",
-				"transcript_last_error":   "",
-				"transcript_last_attempt": &now,
-			}).Error
-		} else if err != nil {
-			status := "failed"
-			if strings.Contains(err.Error(), "429") {
-				status = "rate_limited"
-			}
-			_ = a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Updates(map[string]interface{}{
-				"transcript_status":       status,
-				"transcript_last_error":   err.Error(),
-				"transcript_last_attempt": &now,
-			}).Error
+	// Only YouTube videos have caption tracks; other feeds use their
+	// description. A failed fetch keeps the video queued for its next retry,
+	// and only videos that used up their retries fall back to the description.
+	fetchTranscript := isYouTubeChannel(channel)
+	if strings.TrimSpace(text) == "" && fetchTranscript && video.TranscriptStatus != transcriptStatusGaveUp {
+		transcript, err := a.fetchScheduledTranscript(context.Background(), video)
+		if errors.Is(err, errTranscriptPaused) || errors.Is(err, errTranscriptCooldown) {
+			return "", err
+		}
+		if err == nil {
+			text = transcript.Text
 		}
 	}
+	if strings.TrimSpace(text) == "" {
+		text = strings.TrimSpace(video.Description)
+	}
 	if strings.TrimSpace(text) == "" {
 		return "", fmt.Errorf("transcript not available for this video")
 	}
@@ -1030,6 +1371,10 @@ func (a *AppService) SummarizeVideo(videoID string, templateName string, provide
 		Temperature:  temperature,
 		Title:        video.Title,
 		Channel:      channel.Name,
+		Duration:     services.FormatDuration(video.DurationSeconds),
+		KeyPoints:    services.FormatChapters(decodeChapters(video.Chapters)),
+		Category:     video.Category,
+		Language:     video.Language,
 	})
 	if err != nil {
 		return "", err
@@ -1042,64 +1387,171 @@ func (a *AppService) SummarizeVideo(videoID string, templateName string, provide
 	return summary, nil
 }
 
+func (a *AppService) ResolveChannelID(input string) (string, error) {
+	return a.YouTube.ResolveChannelID(context.Background(), input)
+}
+
 func (a *AppService) SyncChannelFeed(channelID string) (SyncResult, error) {
-	ctx := context.Background()
-	feed, err := a.YouTube.FetchChannelFeed(ctx, channelID)
+	run := a.startSyncRun(SyncTriggerManual)
+	result, err := a.syncChannelFeed(context.Background(), channelID)
+	recorded := result
+	if err != nil {
+		recorded = SyncResult{ChannelID: channelID, Error: err.Error()}
+	}
+	summary := SyncSummary{Channels: []SyncResult{recorded}}
+	tallySyncSummary(&summary)
+	a.finishSyncRun(run, summary, nil)
+	return result, err
+}
+
+// SubscribeFeed adds and syncs a feed from a non-default source such as a
+// podcast or other RSS/Atom feed.
+func (a *AppService) SubscribeFeed(sourceType string, input string) (SyncResult, error) {
+	sourceType = strings.ToLower(strings.TrimSpace(sourceType))
+	if sourceType == "" {
+		sourceType = services.SourceYouTube
+	}
+	source, ok := a.FeedSources[sourceType]
+	if !ok {
+		return SyncResult{}, fmt.Errorf("unsupported feed source: %s", sourceType)
+	}
+	return a.syncSourceFeed(context.Background(), source, input)
+}
+
+func (a *AppService) syncChannelFeed(ctx context.Context, channelID string) (SyncResult, error) {
+	return a.syncSourceFeed(ctx, a.channelSource(channelID), channelID)
+}
+
+// channelSource returns the source a stored channel was subscribed with.
+// Unknown input falls back to YouTube, which resolves handles and URLs.
+func (a *AppService) channelSource(channelID string) services.FeedSource {
+	var channel models.Channel
+	if err := a.DB.Gorm.Select("source_type").Where("channel_id = ?", strings.TrimSpace(channelID)).First(&channel).Error; err == nil {
+		if source, ok := a.FeedSources[channel.SourceType]; ok {
+			return source
+		}
+	}
+	return a.YouTube
+}
+
+func (a *AppService) syncSourceFeed(ctx context.Context, source services.FeedSource, input string) (SyncResult, error) {
+	channelID, err := source.Resolve(ctx, input)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	defer a.scheduleChannelPoll(channelID, true)
+	isYouTube := source.Type() == services.SourceYouTube
+
+	var known models.Channel
+	cache := services.FeedCache{}
+	if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&known).Error; err == nil {
+		cache = services.FeedCache{ETag: known.ETag, LastModified: known.LastModified}
+	}
+
+	feed, err := source.FetchFeed(ctx, channelID, cache)
 	if err != nil {
 		return SyncResult{}, err
 	}
 
+	var metadata *services.ChannelMetadata
+	if isYouTube && channelMetadataDue(known) {
+		meta, err := a.fetchChannelMetadata(ctx, feed.ChannelID)
+		if err != nil && a.logger != nil {
+			a.logger.Printf("channel metadata failed: %s: %v", feed.ChannelID, err)
+		}
+		// A failed lookup still counts as a refresh so it is retried on the
+		// slow schedule rather than on every poll.
+		metadata = &meta
+	}
+
+	if feed.NotModified {
+		if isYouTube {
+			a.classifyStoredVideos(ctx, known, maxSyncClassify)
+		}
+		if metadata != nil {
+			a.syncWriteMu.Lock()
+			err := a.storeChannelMetadata(known.ChannelID, *metadata, time.Now())
+			a.syncWriteMu.Unlock()
+			if err != nil {
+				return SyncResult{}, err
+			}
+		}
+		return SyncResult{
+			ChannelID:   known.ChannelID,
+			ChannelName: known.Name,
+			Unchanged:   true,
+			Muted:       known.Muted,
+		}, nil
+	}
+
+	var removed []uint
+	if isYouTube {
+		removed, err = a.findRemovedVideos(ctx, known.ID, feed.Entries)
+		if err != nil {
+			return SyncResult{}, err
+		}
+		fetched := a.classifyNewEntries(ctx, feed.Entries)
+		a.classifyStoredVideos(ctx, known, maxSyncClassify-fetched)
+		// A first sync would enrich the whole feed; those videos are
+		// enriched lazily when summarized instead.
+		if known.ID != 0 {
+			a.enrichNewEntries(ctx, feed.Entries)
+		}
+	}
+	rules := a.loadRules()
+	defer a.cacheThumbnails(ctx, feed.Entries)
+
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+
+	// The cache validators are saved last: if storing the entries fails, the
+	// next poll must not get a 304 for a feed that was never stored.
 	now := time.Now()
 	channel := models.Channel{
-		ChannelID:   feed.ChannelID,
-		Name:        feed.ChannelName,
-		URL:         feed.ChannelURL,
-		Description: "",
-		UpdatedAt:   now,
+		ChannelID:  feed.ChannelID,
+		SourceType: source.Type(),
+		Name:       feed.ChannelName,
+		URL:        feed.ChannelURL,
+		UpdatedAt:  now,
 	}
 
 	if err := a.DB.Gorm.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "channel_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"name", "url", "thumbnail", "description", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"source_type", "name", "url", "updated_at"}),
 	}).Create(&channel).Error; err != nil {
 		return SyncResult{}, err
 	}
 
+	if metadata != nil {
+		if err := a.storeChannelMetadata(feed.ChannelID, *metadata, now); err != nil {
+			return SyncResult{}, err
+		}
+	}
+
 	var channelRecord models.Channel
 	if err := a.DB.Gorm.Where("channel_id = ?", feed.ChannelID).First(&channelRecord).Error; err != nil {
 		return SyncResult{}, err
 	}
 
-	newCount := 0
-	updatedCount := 0
-
-	for _, entry := range feed.Entries {
-		video := models.Video{
-			VideoID:     entry.VideoID,
-			Title:       entry.Title,
-			URL:         entry.URL,
-			ChannelID:   channelRecord.ID,
-			Thumbnail:   entry.Thumbnail,
-			PublishedAt: entry.PublishedAt,
-			UpdatedAt:   now,
-		}
+	newCount, updatedCount, err := a.storeFeedEntries(channelRecord, feed.Entries, rules, now)
+	if err != nil {
+		return SyncResult{}, err
+	}
 
-		var existing models.Video
-		err := a.DB.Gorm.Where("video_id = ?", entry.VideoID).First(&existing).Error
-		if err == nil {
-			updatedCount++
-		} else if err == gorm.ErrRecordNotFound {
-			newCount++
-		} else if err != nil {
+	if len(removed) > 0 {
+		if err := a.DB.Gorm.Model(&models.Video{}).Where("id IN ?", removed).Updates(map[string]interface{}{
+			"removed":    true,
+			"removed_at": &now,
+		}).Error; err != nil {
 			return SyncResult{}, err
 		}
+	}
 
-		if err := a.DB.Gorm.Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "video_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{"title", "url", "thumbnail", "published_at", "channel_id", "updated_at"}),
-		}).Create(&video).Error; err != nil {
-			return SyncResult{}, err
-		}
+	if err := a.DB.Gorm.Model(&models.Channel{}).Where("id = ?", channelRecord.ID).Updates(map[string]interface{}{
+		"e_tag":         feed.Cache.ETag,
+		"last_modified": feed.Cache.LastModified,
+	}).Error; err != nil {
+		return SyncResult{}, err
 	}
 
 	return SyncResult{
@@ -1107,29 +1559,403 @@ func (a *AppService) SyncChannelFeed(channelID string) (SyncResult, error) {
 		ChannelName:   feed.ChannelName,
 		NewVideos:     newCount,
 		UpdatedVideos: updatedCount,
+		RemovedVideos: len(removed),
+		Muted:         channelRecord.Muted,
 	}, nil
 }
 
+// storeFeedEntries upserts entries for channel and runs the ingest rules on
+// the ones that are new. The caller holds syncWriteMu.
+func (a *AppService) storeFeedEntries(channel models.Channel, entries []services.FeedEntry, rules []compiledRule, now time.Time) (int, int, error) {
+	newCount := 0
+	updatedCount := 0
+	for _, entry := range entries {
+		_, created, err := a.storeFeedEntry(channel, entry, rules, now)
+		if err != nil {
+			return newCount, updatedCount, err
+		}
+		if created {
+			newCount++
+		} else {
+			updatedCount++
+		}
+	}
+	return newCount, updatedCount, nil
+}
+
+// storeFeedEntry upserts one entry and runs the ingest rules when it is new.
+// The caller holds syncWriteMu.
+func (a *AppService) storeFeedEntry(channel models.Channel, entry services.FeedEntry, rules []compiledRule, now time.Time) (models.Video, bool, error) {
+	video, created, err := a.upsertFeedEntry(channel.ID, entry, now)
+	if err != nil {
+		return video, created, err
+	}
+	if created {
+		if err := a.applyRules(rules, video, channel.ChannelID, nil); err != nil && a.logger != nil {
+			a.logger.Printf("apply rules failed: %s: %v", video.VideoID, err)
+		}
+	}
+	return video, created, nil
+}
+
+// maxSyncClassify bounds how many watch pages one sync fetches to classify
+// videos. Videos left over keep an empty type and are picked up by a later
+// sync.
+const maxSyncClassify = 5
+
+// classifyNewEntries fills VideoType for entries that are not stored yet and
+// could not be classified from their link alone. It returns the number of
+// watch pages fetched. A failed fetch leaves the type empty; the stored video
+// is classified again by classifyStoredVideos.
+func (a *AppService) classifyNewEntries(ctx context.Context, entries []services.FeedEntry) int {
+	fetched := 0
+	for i := range entries {
+		if fetched >= maxSyncClassify {
+			break
+		}
+		if entries[i].VideoType != "" {
+			continue
+		}
+		var count int64
+		if err := a.DB.Gorm.Model(&models.Video{}).Where("video_id = ?", entries[i].VideoID).Count(&count).Error; err != nil || count > 0 {
+			continue
+		}
+		fetched++
+		videoType, err := a.YouTube.ClassifyVideo(ctx, entries[i].VideoID)
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Printf("classify video failed: %s: %v", entries[i].VideoID, err)
+			}
+			continue
+		}
+		entries[i].VideoType = videoType
+	}
+	return fetched
+}
+
+// classifyStoredVideos classifies up to limit of a channel's stored videos
+// that have no type yet, newest first, such as backfilled videos or ones
+// whose fetch failed. Rules with a video type condition run again for each
+// classified video.
+func (a *AppService) classifyStoredVideos(ctx context.Context, channel models.Channel, limit int) {
+	if channel.ID == 0 || limit <= 0 {
+		return
+	}
+	var videos []models.Video
+	if err := a.DB.Gorm.
+		Where("channel_id = ? AND removed = ? AND (video_type IS NULL OR video_type = '')", channel.ID, false).
+		Order("published_at desc").
+		Limit(limit).
+		Find(&videos).Error; err != nil {
+		if a.logger != nil {
+			a.logger.Printf("load unclassified videos failed: %s: %v", channel.ChannelID, err)
+		}
+		return
+	}
+
+	rules := a.loadRules()
+	for _, video := range videos {
+		videoType, err := a.YouTube.ClassifyVideo(ctx, video.VideoID)
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Printf("classify video failed: %s: %v", video.VideoID, err)
+			}
+			continue
+		}
+		video.VideoType = videoType
+		a.syncWriteMu.Lock()
+		err = a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Update("video_type", videoType).Error
+		if err == nil {
+			err = a.applyRules(rules, video, channel.ChannelID, compiledRule.hasVideoType)
+		}
+		a.syncWriteMu.Unlock()
+		if err != nil && a.logger != nil {
+			a.logger.Printf("store video type failed: %s: %v", video.VideoID, err)
+		}
+	}
+}
+
+// findRemovedVideos returns stored videos that fall inside the window covered
+// by entries but are missing from it, and that YouTube confirms are gone or
+// private.
+func (a *AppService) findRemovedVideos(ctx context.Context, channelID uint, entries []services.FeedEntry) ([]uint, error) {
+	if channelID == 0 || len(entries) == 0 {
+		return nil, nil
+	}
+
+	oldest := entries[0].PublishedAt
+	present := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.PublishedAt.Before(oldest) {
+			oldest = entry.PublishedAt
+		}
+		present = append(present, entry.VideoID)
+	}
+
+	var candidates []models.Video
+	if err := a.DB.Gorm.
+		Where("channel_id = ? AND removed = ? AND published_at > ? AND video_id NOT IN ?", channelID, false, oldest, present).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	removed := make([]uint, 0, len(candidates))
+	for _, v := range candidates {
+		available, err := a.YouTube.CheckVideoAvailable(ctx, v.VideoID)
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Printf("check video availability failed: %s: %v", v.VideoID, err)
+			}
+			continue
+		}
+		if !available {
+			removed = append(removed, v.ID)
+		}
+	}
+	return removed, nil
+}
+
+func (a *AppService) upsertFeedEntry(channelID uint, entry services.FeedEntry, now time.Time) (models.Video, bool, error) {
+	video := models.Video{
+		VideoID:       entry.VideoID,
+		Title:         entry.Title,
+		URL:           entry.URL,
+		ChannelID:     channelID,
+		VideoType:     entry.VideoType,
+		Thumbnail:     entry.Thumbnail,
+		Description:   entry.Description,
+		MediaURL:      entry.MediaURL,
+		MediaType:     entry.MediaType,
+		ViewCount:     entry.Views,
+		RatingCount:   entry.RatingCount,
+		RatingAverage: entry.Rating,
+		PublishedAt:   entry.PublishedAt,
+		UpdatedAt:     now,
+	}
+
+	created := false
+	var existing models.Video
+	err := a.DB.Gorm.Where("video_id = ?", entry.VideoID).First(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		created = true
+	} else if err != nil {
+		return models.Video{}, false, err
+	}
+
+	if !created {
+		var revisions []models.VideoRevision
+		if entry.Title != "" && existing.Title != entry.Title {
+			revisions = append(revisions, models.VideoRevision{
+				VideoID:   existing.ID,
+				Field:     "title",
+				OldValue:  existing.Title,
+				NewValue:  entry.Title,
+				ChangedAt: now,
+			})
+		}
+		if entry.Thumbnail != "" && !sameThumbnail(existing.Thumbnail, entry.Thumbnail) {
+			revisions = append(revisions, models.VideoRevision{
+				VideoID:   existing.ID,
+				Field:     "thumbnail",
+				OldValue:  existing.Thumbnail,
+				NewValue:  entry.Thumbnail,
+				ChangedAt: now,
+			})
+			a.Thumbnails.Remove(existing.VideoID)
+		}
+		if len(revisions) > 0 {
+			if err := a.DB.Gorm.Create(&revisions).Error; err != nil {
+				return models.Video{}, false, err
+			}
+		}
+	}
+
+	if entry.Metadata != nil {
+		applyVideoMetadata(&video, *entry.Metadata, now)
+	}
+
+	columns := []string{"title", "url", "thumbnail", "media_url", "media_type", "view_count", "rating_count", "rating_average", "published_at", "removed", "removed_at", "updated_at"}
+	if channelID != 0 {
+		columns = append(columns, "channel_id")
+	}
+	if entry.VideoType != "" {
+		columns = append(columns, "video_type")
+	}
+	if entry.Metadata != nil {
+		columns = append(columns, videoMetadataColumns...)
+	} else if created || existing.EnrichedAt == nil {
+		// An enriched video keeps the full yt-dlp description.
+		columns = append(columns, "description")
+	}
+	if err := a.DB.Gorm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "video_id"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(&video).Error; err != nil {
+		return models.Video{}, false, err
+	}
+
+	var saved models.Video
+	if err := a.DB.Gorm.Where("video_id = ?", entry.VideoID).First(&saved).Error; err != nil {
+		return models.Video{}, false, err
+	}
+
+	if entry.Views > 0 || entry.RatingCount > 0 {
+		stat := models.VideoStat{
+			VideoID:       saved.ID,
+			ViewCount:     entry.Views,
+			RatingCount:   entry.RatingCount,
+			RatingAverage: entry.Rating,
+			RecordedAt:    now,
+		}
+		if err := a.DB.Gorm.Create(&stat).Error; err != nil {
+			return models.Video{}, false, err
+		}
+	}
+	return saved, created, nil
+}
+
+func (a *AppService) ListVideoRevisions(videoID string) ([]models.VideoRevision, error) {
+	if strings.TrimSpace(videoID) == "" {
+		return nil, fmt.Errorf("videoID is required")
+	}
+	var video models.Video
+	if err := a.DB.Gorm.Where("video_id = ?", videoID).First(&video).Error; err != nil {
+		return nil, err
+	}
+	var revisions []models.VideoRevision
+	if err := a.DB.Gorm.Where("video_id = ?", video.ID).Order("changed_at desc").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (a *AppService) ListVideoStats(videoID string) ([]models.VideoStat, error) {
+	if strings.TrimSpace(videoID) == "" {
+		return nil, fmt.Errorf("videoID is required")
+	}
+	var video models.Video
+	if err := a.DB.Gorm.Where("video_id = ?", videoID).First(&video).Error; err != nil {
+		return nil, err
+	}
+	var stats []models.VideoStat
+	if err := a.DB.Gorm.Where("video_id = ?", video.ID).Order("recorded_at asc").Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
 func (a *AppService) SyncAllChannels() (SyncSummary, error) {
-	channels, err := a.ListChannels()
+	return a.SyncAllChannelsFrom(SyncTriggerManual)
+}
+
+// SyncAllChannelsFrom syncs every channel and playlist and records the run
+// under trigger (manual, tray or scheduler).
+func (a *AppService) SyncAllChannelsFrom(trigger string) (SyncSummary, error) {
+	run := a.startSyncRun(trigger)
+	var channels []models.Channel
+	if err := a.DB.Gorm.Where("paused = ?", false).Order("name asc").Find(&channels).Error; err != nil {
+		a.finishSyncRun(run, SyncSummary{}, err)
+		return SyncSummary{}, err
+	}
+	playlists, err := a.ListPlaylists()
 	if err != nil {
+		a.finishSyncRun(run, SyncSummary{}, err)
 		return SyncSummary{}, err
 	}
+	summary := a.syncFeeds(channels, playlists)
+	a.finishSyncRun(run, summary, nil)
+	return summary, nil
+}
+
+func (a *AppService) syncFeeds(channels []models.Channel, playlists []PlaylistItem) SyncSummary {
+	settings := a.GetSyncSettings()
+	timeout := time.Duration(settings.ChannelTimeoutSeconds) * time.Second
 
 	summary := SyncSummary{
-		Channels: make([]SyncResult, 0, len(channels)),
+		Channels:  make([]SyncResult, len(channels)),
+		Playlists: make([]PlaylistSyncResult, len(playlists)),
 	}
 
-	for _, channel := range channels {
-		result, err := a.SyncChannelFeed(channel.ChannelID)
+	runBounded(settings.Concurrency, len(channels), func(i int) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		result, err := a.syncChannelFeed(ctx, channels[i].ChannelID)
 		if err != nil {
-			return summary, err
+			result = SyncResult{
+				ChannelID:   channels[i].ChannelID,
+				ChannelName: channels[i].Name,
+				Error:       err.Error(),
+			}
+			if a.logger != nil {
+				a.logger.Printf("sync channel failed: %s: %v", channels[i].ChannelID, err)
+			}
+		}
+		summary.Channels[i] = result
+	})
+
+	runBounded(settings.Concurrency, len(playlists), func(i int) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		result, err := a.syncPlaylistFeed(ctx, playlists[i].PlaylistID)
+		if err != nil {
+			result = PlaylistSyncResult{
+				PlaylistID: playlists[i].PlaylistID,
+				Title:      playlists[i].Title,
+				Error:      err.Error(),
+			}
+			if a.logger != nil {
+				a.logger.Printf("sync playlist failed: %s: %v", playlists[i].PlaylistID, err)
+			}
+		}
+		summary.Playlists[i] = result
+	})
+
+	tallySyncSummary(&summary)
+	return summary
+}
+
+func tallySyncSummary(summary *SyncSummary) {
+	for _, result := range summary.Channels {
+		if result.Error != "" {
+			summary.TotalFailed++
+			continue
+		}
+		if result.Unchanged {
+			summary.TotalUnchanged++
+			continue
+		}
+		// New videos from muted channels do not count toward notifications.
+		if !result.Muted {
+			summary.TotalNew += result.NewVideos
+		}
+		summary.TotalUpdated += result.UpdatedVideos
+	}
+	for _, result := range summary.Playlists {
+		if result.Error != "" {
+			summary.TotalFailed++
+			continue
 		}
 		summary.TotalNew += result.NewVideos
 		summary.TotalUpdated += result.UpdatedVideos
-		summary.Channels = append(summary.Channels, result)
 	}
-	return summary, nil
+}
+
+func runBounded(limit int, n int, fn func(i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
 }
 
 func (a *AppService) AutoSummarizePending() (int, error) {
@@ -1193,17 +2019,41 @@ func (a *AppService) AutoSummarizePending() (int, error) {
 	}
 
 	var videos []models.Video
-	if err := a.DB.Gorm.Where("summary = '' OR summary IS NULL").Order("published_at desc").Limit(limit).Find(&videos).Error; err != nil {
+	if err := a.DB.Gorm.Table("videos").
+		Select("videos.*").
+		Joins("left join channels on channels.id = videos.channel_id").
+		Where("(videos.summary = '' OR videos.summary IS NULL)").
+		Where("videos.muted = ? AND videos.skip_summary = ?", false, false).
+		Where("(videos.transcript_next_attempt IS NULL OR videos.transcript_next_attempt <= ?)", time.Now()).
+		Where("(channels.muted IS NULL OR channels.muted = ?)", false).
+		Where("(channels.summary_exclude_types IS NULL OR channels.summary_exclude_types = '' OR videos.video_type IS NULL OR videos.video_type = '' OR instr(',' || channels.summary_exclude_types || ',', ',' || videos.video_type || ',') = 0)").
+		Where("videos.channel_id NOT IN (?)", a.DB.Gorm.Table("channel_group_members").
+			Select("channel_group_members.channel_id").
+			Joins("inner join channel_groups on channel_groups.id = channel_group_members.channel_group_id").
+			Where("channel_groups.auto_summary = ?", false)).
+		Order("videos.published_at desc").
+		Limit(limit).
+		Find(&videos).Error; err != nil {
 		return 0, err
 	}
 
 	count := 0
 	for _, v := range videos {
-		_, err := a.SummarizeVideo(v.VideoID, templateName, provider, model, baseURL, apiKey, 0.4)
+		videoTemplate := templateName
+		if v.TemplateName != "" {
+			videoTemplate = v.TemplateName
+		} else if groupTemplate := a.groupTemplateName(v.ChannelID); groupTemplate != "" {
+			videoTemplate = groupTemplate
+		}
+		_, err := a.SummarizeVideo(v.VideoID, videoTemplate, provider, model, baseURL, apiKey, 0.4)
 		if err != nil {
-			if strings.Contains(err.Error(), "cooldown") {
+			if errors.Is(err, errTranscriptCooldown) {
 				continue
 			}
+			if errors.Is(err, errTranscriptPaused) {
+				// Every later video would hit the same pause.
+				break
+			}
 			if a.logger != nil {
 				a.logger.Printf("auto summary failed: %s: %v", v.VideoID, err)
 			}
@@ -1239,6 +2089,8 @@ func applyTemplate(prompt string, req SummarizeRequest) string {
 		"{channel}", req.Channel,
 		"{duration}", req.Duration,
 		"{key_points}", req.KeyPoints,
+		"{category}", req.Category,
+		"{language}", req.Language,
 		"{summary}", req.Text,
 		"{text}", req.Text,
 	)
@@ -1269,25 +2121,17 @@ func applySystemLanguage(prompt string, lang string) string {
 	}
 }
 
-func shouldSkipTranscriptFetch(video models.Video) (bool, time.Duration) {
-	if video.TranscriptLastAttempt == nil {
-		return false, 0
-	}
-	status := strings.ToLower(strings.TrimSpace(video.TranscriptStatus))
-	elapsed := time.Since(*video.TranscriptLastAttempt)
-	if status == "rate_limited" {
-		const cooldown = 6 * time.Hour
-		if elapsed < cooldown {
-			return true, cooldown - elapsed
-		}
+// sameThumbnail ignores the i1..i4.ytimg.com host rotation between feed fetches.
+func sameThumbnail(a, b string) bool {
+	if a == b {
+		return true
 	}
-	if status == "failed" {
-		const cooldown = 30 * time.Minute
-		if elapsed < cooldown {
-			return true, cooldown - elapsed
-		}
+	pa, errA := url.Parse(a)
+	pb, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return false
 	}
-	return false, 0
+	return pa.Path == pb.Path && pa.RawQuery == pb.RawQuery
 }
 
 func applyTagLanguage(prompt string, lang string) string {
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+// newTestAppService opens an app service on a fresh database. The working
+// directory moves to a temp dir so the app log does not land in the tree.
+func newTestAppService(t *testing.T) *AppService {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	a, err := NewAppService(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("NewAppService: %v", err)
+	}
+	// Watch pages and feeds are served by a stand-in so no test reaches
+	// YouTube; tests that need pages point BaseURL at their own server.
+	offline := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(offline.Close)
+	a.YouTube.BaseURL = offline.URL
+	t.Cleanup(func() {
+		if sqlDB, err := a.DB.Gorm.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	return a
+}
+
+func saveTestSecret(t *testing.T, a *AppService, key string, value string) {
+	t.Helper()
+	enc, err := encryptString(value)
+	if err != nil {
+		t.Fatalf("encrypt %s: %v", key, err)
+	}
+	setSetting(a.DB, key, enc)
+}
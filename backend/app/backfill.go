@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+type BackfillResult struct {
+	ChannelID     string
+	Pages         int
+	NewVideos     int
+	SkippedVideos int
+}
+
+// BackfillChannel pages through the channel's uploads playlist with the
+// YouTube Data API and stores videos older than the RSS window. maxVideos
+// of 0 fetches the whole history. Rows are written directly rather than via
+// a sync, so they never show up as new videos in sync notifications.
+func (a *AppService) BackfillChannel(channelID string, maxVideos int) (BackfillResult, error) {
+	if strings.TrimSpace(channelID) == "" {
+		return BackfillResult{}, fmt.Errorf("channelID is required")
+	}
+
+	settings, err := a.GetAppSettings()
+	if err != nil {
+		return BackfillResult{}, err
+	}
+	if strings.TrimSpace(settings.YouTubeAPIKey) == "" {
+		return BackfillResult{}, fmt.Errorf("youtube api key is required for backfill")
+	}
+
+	api := a.youTubeDataAPI(settings)
+	if api == nil {
+		return BackfillResult{}, fmt.Errorf("youtube data api is not configured")
+	}
+
+	ctx := context.Background()
+	channelID, err = a.YouTube.ResolveChannelID(ctx, channelID)
+	if err != nil {
+		return BackfillResult{}, err
+	}
+
+	var channel models.Channel
+	if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&channel).Error; err != nil {
+		return BackfillResult{}, fmt.Errorf("channel %s is not subscribed: %w", channelID, err)
+	}
+
+	uploads, err := api.UploadsPlaylistID(ctx, settings.YouTubeAPIKey, channelID)
+	if err != nil {
+		return BackfillResult{}, err
+	}
+
+	result := BackfillResult{ChannelID: channelID}
+	pageToken := ""
+	for {
+		page, err := api.ListPlaylistItems(ctx, settings.YouTubeAPIKey, uploads, pageToken)
+		if err != nil {
+			return result, err
+		}
+		result.Pages++
+		a.classifyNewEntries(ctx, page.Entries)
+
+		if err := a.storeBackfillPage(channel.ID, page.Entries, &result, maxVideos); err != nil {
+			return result, err
+		}
+
+		if page.NextPageToken == "" || (maxVideos > 0 && result.NewVideos >= maxVideos) {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return result, nil
+}
+
+func (a *AppService) storeBackfillPage(channelID uint, entries []services.FeedEntry, result *BackfillResult, maxVideos int) error {
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+
+	now := time.Now()
+	for _, entry := range entries {
+		if maxVideos > 0 && result.NewVideos >= maxVideos {
+			return nil
+		}
+		// Rows the RSS sync already owns keep their feed statistics.
+		var count int64
+		if err := a.DB.Gorm.Model(&models.Video{}).Where("video_id = ?", entry.VideoID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			result.SkippedVideos++
+			continue
+		}
+		if _, _, err := a.upsertFeedEntry(channelID, entry, now); err != nil {
+			return err
+		}
+		result.NewVideos++
+	}
+	return nil
+}
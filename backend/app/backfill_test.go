@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ytfeedgenerator/backend/models"
+)
+
+const testChannelID = "UCabcdefghijklmnopqrstuv"
+
+func newYouTubeAPIStandIn(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "test-key" {
+			http.Error(w, "bad key", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/channels":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []interface{}{map[string]interface{}{
+					"contentDetails": map[string]interface{}{
+						"relatedPlaylists": map[string]string{"uploads": "UUuploads"},
+					},
+				}},
+			})
+		case "/playlistItems":
+			item := func(id string) map[string]interface{} {
+				return map[string]interface{}{
+					"snippet":        map[string]string{"title": "Video " + id, "videoOwnerChannelId": testChannelID},
+					"contentDetails": map[string]string{"videoId": id, "videoPublishedAt": "2024-01-02T03:04:05Z"},
+				}
+			}
+			if r.URL.Query().Get("pageToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"nextPageToken": "page2",
+					"items":         []interface{}{item("vid00000001")},
+				})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []interface{}{item("vid00000002")},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBackfillChannelUsesConfiguredAPI(t *testing.T) {
+	tests := []struct {
+		name        string
+		settingBase bool
+	}{
+		// The base URL setting copies the client of the default service.
+		{name: "base url setting", settingBase: true},
+		// Without the setting the default service itself is used.
+		{name: "default service", settingBase: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAppService(t)
+			srv := newYouTubeAPIStandIn(t)
+			saveTestSecret(t, a, "youtube_api_key", "test-key")
+			if tt.settingBase {
+				setSetting(a.DB, "youtube_api_base_url", srv.URL)
+			} else {
+				a.YouTubeData.BaseURL = srv.URL
+			}
+			if err := a.DB.Gorm.Create(&models.Channel{ChannelID: testChannelID, Name: "Test"}).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := a.BackfillChannel(testChannelID, 0)
+			if err != nil {
+				t.Fatalf("BackfillChannel: %v", err)
+			}
+			if result.Pages != 2 || result.NewVideos != 2 {
+				t.Fatalf("got %+v, want 2 pages and 2 new videos", result)
+			}
+			var count int64
+			a.DB.Gorm.Model(&models.Video{}).Count(&count)
+			if count != 2 {
+				t.Fatalf("stored %d videos, want 2", count)
+			}
+		})
+	}
+}
+
+func TestFetchChannelMetadataWithoutDataService(t *testing.T) {
+	a := newTestAppService(t)
+	saveTestSecret(t, a, "youtube_api_key", "test-key")
+	a.YouTubeData = nil
+
+	// No Data API service: the channel page scrape takes over.
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/channel/"+testChannelID+"/about" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<meta property="og:description" content="scraped">`))
+	}))
+	t.Cleanup(page.Close)
+	a.YouTube.BaseURL = page.URL
+
+	meta, err := a.fetchChannelMetadata(context.Background(), testChannelID)
+	if err != nil {
+		t.Fatalf("fetchChannelMetadata: %v", err)
+	}
+	if meta.Description != "scraped" {
+		t.Fatalf("description = %q, want the scraped one", meta.Description)
+	}
+
+	if _, err := a.BackfillChannel(testChannelID, 0); err == nil {
+		t.Fatal("BackfillChannel without a Data API service succeeded")
+	}
+}
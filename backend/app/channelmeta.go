@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+const channelMetadataRefreshInterval = 7 * 24 * time.Hour
+
+// RefreshChannelMetadata forces a metadata refresh outside the weekly cycle
+// that normal syncs follow.
+func (a *AppService) RefreshChannelMetadata(channelID string) (models.Channel, error) {
+	if strings.TrimSpace(channelID) == "" {
+		return models.Channel{}, fmt.Errorf("channelID is required")
+	}
+	meta, err := a.fetchChannelMetadata(context.Background(), channelID)
+	if err != nil {
+		return models.Channel{}, err
+	}
+
+	a.syncWriteMu.Lock()
+	err = a.storeChannelMetadata(channelID, meta, time.Now())
+	a.syncWriteMu.Unlock()
+	if err != nil {
+		return models.Channel{}, err
+	}
+
+	var channel models.Channel
+	if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&channel).Error; err != nil {
+		return models.Channel{}, err
+	}
+	return channel, nil
+}
+
+func channelMetadataDue(channel models.Channel) bool {
+	if channel.MetadataRefreshedAt == nil {
+		return true
+	}
+	return time.Since(*channel.MetadataRefreshedAt) > channelMetadataRefreshInterval
+}
+
+// fetchChannelMetadata runs inside sync workers, so a missing Data API
+// service falls back to scraping instead of failing the sync.
+func (a *AppService) fetchChannelMetadata(ctx context.Context, channelID string) (services.ChannelMetadata, error) {
+	settings, err := a.GetAppSettings()
+	if err == nil && strings.TrimSpace(settings.YouTubeAPIKey) != "" {
+		if api := a.youTubeDataAPI(settings); api != nil {
+			return api.ChannelMetadata(ctx, settings.YouTubeAPIKey, channelID)
+		}
+	}
+	return a.YouTube.FetchChannelMetadata(ctx, channelID)
+}
+
+// youTubeDataAPI returns the Data API service, pointed at the base URL
+// override when one is set. It is nil when the service was never set up.
+func (a *AppService) youTubeDataAPI(settings AppSettings) *services.YouTubeDataService {
+	base := strings.TrimSpace(settings.YouTubeAPIBaseURL)
+	if a.YouTubeData == nil {
+		if base == "" {
+			return nil
+		}
+		return &services.YouTubeDataService{BaseURL: base}
+	}
+	if base != "" {
+		return &services.YouTubeDataService{Client: a.YouTubeData.Client, BaseURL: base}
+	}
+	return a.YouTubeData
+}
+
+// storeChannelMetadata only writes fields that were found, so a partial
+// scrape never blanks out values from an earlier refresh.
+func (a *AppService) storeChannelMetadata(channelID string, meta services.ChannelMetadata, now time.Time) error {
+	updates := map[string]interface{}{
+		"metadata_refreshed_at": &now,
+	}
+	if meta.Thumbnail != "" {
+		updates["thumbnail"] = meta.Thumbnail
+	}
+	if meta.Description != "" {
+		updates["description"] = meta.Description
+	}
+	if meta.SubscriberCount > 0 {
+		updates["subscriber_count"] = meta.SubscriberCount
+	}
+	if meta.CreatedAt != nil {
+		updates["channel_created_at"] = meta.CreatedAt
+	}
+	return a.DB.Gorm.Model(&models.Channel{}).Where("channel_id = ?", channelID).Updates(updates).Error
+}
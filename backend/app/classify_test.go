@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+func TestSyncRetriesFailedClassification(t *testing.T) {
+	a := newTestAppService(t)
+	feed := `<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <yt:channelId>` + testChannelID + `</yt:channelId>
+  <title>Test</title>
+  <entry>
+    <id>yt:video:liveVideo01</id>
+    <yt:videoId>liveVideo01</yt:videoId>
+    <yt:channelId>` + testChannelID + `</yt:channelId>
+    <title>Stream</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=liveVideo01"/>
+    <published>2026-10-01T00:00:00+00:00</published>
+  </entry>
+</feed>`
+	var mu sync.Mutex
+	watchFetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feeds/videos.xml":
+			_, _ = w.Write([]byte(feed))
+		case "/watch":
+			mu.Lock()
+			watchFetches++
+			first := watchFetches == 1
+			mu.Unlock()
+			if first {
+				http.Error(w, "busy", http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"isLiveContent": true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	a.YouTube.BaseURL = server.URL
+
+	if _, err := a.SaveRule(RuleInput{Name: "mute live", Enabled: true, VideoType: "live", Mute: true}); err != nil {
+		t.Fatalf("SaveRule: %v", err)
+	}
+
+	if _, err := a.syncChannelFeed(t.Context(), testChannelID); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	var video models.Video
+	if err := a.DB.Gorm.Where("video_id = ?", "liveVideo01").First(&video).Error; err != nil {
+		t.Fatal(err)
+	}
+	if video.VideoType != "" || video.Muted {
+		t.Fatalf("failed classification stored type %q, muted=%v", video.VideoType, video.Muted)
+	}
+
+	if _, err := a.syncChannelFeed(t.Context(), testChannelID); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if err := a.DB.Gorm.First(&video, video.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if video.VideoType != "live" || !video.Muted {
+		t.Fatalf("retry: type=%q muted=%v, want live and muted by the rule", video.VideoType, video.Muted)
+	}
+}
+
+func TestClassifyNewEntriesIsCapped(t *testing.T) {
+	a := newTestAppService(t)
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write([]byte(`<html></html>`))
+	}))
+	t.Cleanup(server.Close)
+	a.YouTube.BaseURL = server.URL
+
+	entries := make([]services.FeedEntry, maxSyncClassify+3)
+	for i := range entries {
+		entries[i].VideoID = fmt.Sprintf("video%06d", i)
+	}
+	if got := a.classifyNewEntries(t.Context(), entries); got != maxSyncClassify || fetches != maxSyncClassify {
+		t.Fatalf("fetched %d pages (reported %d), want %d", fetches, got, maxSyncClassify)
+	}
+	if entries[maxSyncClassify].VideoType != "" {
+		t.Fatal("entry past the cap was classified")
+	}
+}
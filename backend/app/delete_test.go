@@ -0,0 +1,117 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+)
+
+// attachVideoRows gives a video one row in every table that refers to it.
+func attachVideoRows(t *testing.T, a *AppService, video models.Video, playlistID uint) {
+	t.Helper()
+	tag := models.Tag{Name: "tag-" + video.VideoID, CreatedAt: time.Now()}
+	collection := models.Collection{Name: "collection-" + video.VideoID}
+	for _, row := range []interface{}{&tag, &collection} {
+		if err := a.DB.Gorm.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, row := range []interface{}{
+		&models.TranscriptSegment{VideoID: video.ID, Text: "line"},
+		&models.VideoStat{VideoID: video.ID, RecordedAt: time.Now()},
+		&models.VideoRevision{VideoID: video.ID, Field: "title", ChangedAt: time.Now()},
+		&models.CollectionVideo{CollectionID: collection.ID, VideoID: video.ID},
+		&models.PlaylistVideo{PlaylistID: playlistID, VideoID: video.ID},
+	} {
+		if err := a.DB.Gorm.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.DB.Gorm.Model(&video).Association("Tags").Append(&tag); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeCachedThumbnail puts a thumbnail for videoID into the cache and
+// returns its path.
+func writeCachedThumbnail(t *testing.T, a *AppService, videoID string) string {
+	t.Helper()
+	if err := os.MkdirAll(a.Thumbnails.Dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(a.Thumbnails.Dir, videoID+".jpg")
+	if err := os.WriteFile(path, []byte("jpeg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// assertNoVideoRows fails if any row still refers to the video.
+func assertNoVideoRows(t *testing.T, a *AppService, videoID uint) {
+	t.Helper()
+	for _, table := range []string{"transcript_segments", "video_stats", "video_revisions", "collection_videos", "playlist_videos", "video_tags"} {
+		var count int64
+		if err := a.DB.Gorm.Table(table).Where("video_id = ?", videoID).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("%s keeps %d rows for deleted video %d", table, count, videoID)
+		}
+	}
+	var count int64
+	a.DB.Gorm.Model(&models.Video{}).Where("id = ?", videoID).Count(&count)
+	if count != 0 {
+		t.Errorf("video %d not deleted", videoID)
+	}
+}
+
+func TestDeleteChannelRemovesVideoRows(t *testing.T) {
+	a := newTestAppService(t)
+	playlist := models.Playlist{PlaylistID: "PLtest0123456789"}
+	a.DB.Gorm.Create(&playlist)
+	video := createTestVideo(t, a, models.Video{VideoID: "dQw4w9WgXcQ"})
+	attachVideoRows(t, a, video, playlist.ID)
+	thumb := writeCachedThumbnail(t, a, video.VideoID)
+
+	if err := a.DeleteChannel(testChannelID); err != nil {
+		t.Fatalf("DeleteChannel: %v", err)
+	}
+	assertNoVideoRows(t, a, video.ID)
+	if _, err := os.Stat(thumb); !os.IsNotExist(err) {
+		t.Fatalf("cached thumbnail kept: %v", err)
+	}
+}
+
+func TestDeletePlaylistRemovesOrphanedVideoRows(t *testing.T) {
+	a := newTestAppService(t)
+	playlist := models.Playlist{PlaylistID: "PLtest0123456789"}
+	a.DB.Gorm.Create(&playlist)
+
+	orphan := models.Video{VideoID: "orphanVid01"}
+	a.DB.Gorm.Create(&orphan)
+	attachVideoRows(t, a, orphan, playlist.ID)
+	owned := createTestVideo(t, a, models.Video{VideoID: "ownedVid001"})
+	a.DB.Gorm.Create(&models.PlaylistVideo{PlaylistID: playlist.ID, VideoID: owned.ID})
+	orphanThumb := writeCachedThumbnail(t, a, orphan.VideoID)
+	ownedThumb := writeCachedThumbnail(t, a, owned.VideoID)
+
+	if err := a.DeletePlaylist(playlist.PlaylistID); err != nil {
+		t.Fatalf("DeletePlaylist: %v", err)
+	}
+	assertNoVideoRows(t, a, orphan.ID)
+	if _, err := os.Stat(orphanThumb); !os.IsNotExist(err) {
+		t.Fatalf("orphan thumbnail kept: %v", err)
+	}
+	if _, err := os.Stat(ownedThumb); err != nil {
+		t.Fatalf("owned thumbnail removed: %v", err)
+	}
+
+	var count int64
+	a.DB.Gorm.Model(&models.Video{}).Where("id = ?", owned.ID).Count(&count)
+	if count != 1 {
+		t.Fatal("a subscribed channel's video was deleted with the playlist")
+	}
+}
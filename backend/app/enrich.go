@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+// maxSyncEnrich bounds how many new videos one channel sync enriches, since
+// each yt-dlp run takes seconds and counts against the channel timeout.
+const maxSyncEnrich = 5
+
+var errEnrichmentDisabled = errors.New("yt-dlp enrichment is disabled")
+
+var videoMetadataColumns = []string{"duration_seconds", "chapters", "category", "language", "enriched_at", "description"}
+
+// EnrichVideo runs yt-dlp for one stored video and saves its duration,
+// full description, chapters, category and language.
+func (a *AppService) EnrichVideo(videoID string) (models.Video, error) {
+	if strings.TrimSpace(videoID) == "" {
+		return models.Video{}, fmt.Errorf("videoID is required")
+	}
+	var video models.Video
+	if err := a.DB.Gorm.Where("video_id = ?", videoID).First(&video).Error; err != nil {
+		return models.Video{}, err
+	}
+	return a.enrichStoredVideo(context.Background(), video)
+}
+
+func (a *AppService) enrichStoredVideo(ctx context.Context, video models.Video) (models.Video, error) {
+	settings, err := a.GetAppSettings()
+	if err != nil {
+		return video, err
+	}
+	if !settings.YTDLPEnabled {
+		return video, errEnrichmentDisabled
+	}
+
+	meta, err := a.YTDLP.FetchMetadata(ctx, settings.YTDLPPath, video.URL)
+	if err != nil {
+		return video, err
+	}
+	// Duration rules could not match at ingest without a duration.
+	rerunRules := video.DurationSeconds <= 0 && meta.DurationSeconds > 0
+	var rules []compiledRule
+	var channel models.Channel
+	if rerunRules {
+		rules = a.loadRules()
+		_ = a.DB.Gorm.Select("channel_id").First(&channel, video.ChannelID).Error
+	}
+
+	now := time.Now()
+	applyVideoMetadata(&video, meta, now)
+	updates := map[string]interface{}{
+		"duration_seconds": video.DurationSeconds,
+		"chapters":         video.Chapters,
+		"category":         video.Category,
+		"language":         video.Language,
+		"enriched_at":      video.EnrichedAt,
+		"description":      video.Description,
+	}
+
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+	if err := a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Updates(updates).Error; err != nil {
+		return video, err
+	}
+	if rerunRules && len(rules) > 0 {
+		if err := a.applyRules(rules, video, channel.ChannelID, compiledRule.hasDuration); err != nil {
+			return video, err
+		}
+		if err := a.DB.Gorm.First(&video, video.ID).Error; err != nil {
+			return video, err
+		}
+	}
+	return video, nil
+}
+
+// enrichNewEntries attaches yt-dlp metadata to entries that are not stored
+// yet, so ingest rules can match on duration.
+func (a *AppService) enrichNewEntries(ctx context.Context, entries []services.FeedEntry) {
+	settings, err := a.GetAppSettings()
+	if err != nil || !settings.YTDLPEnabled {
+		return
+	}
+	enriched := 0
+	for i := range entries {
+		if enriched >= maxSyncEnrich {
+			return
+		}
+		var count int64
+		if err := a.DB.Gorm.Model(&models.Video{}).Where("video_id = ?", entries[i].VideoID).Count(&count).Error; err != nil || count > 0 {
+			continue
+		}
+		enriched++
+		meta, err := a.YTDLP.FetchMetadata(ctx, settings.YTDLPPath, entries[i].URL)
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Printf("enrich video failed: %s: %v", entries[i].VideoID, err)
+			}
+			continue
+		}
+		entries[i].Metadata = &meta
+	}
+}
+
+func applyVideoMetadata(video *models.Video, meta services.VideoMetadata, now time.Time) {
+	video.DurationSeconds = meta.DurationSeconds
+	video.Category = meta.Category
+	video.Language = meta.Language
+	video.Chapters = ""
+	if len(meta.Chapters) > 0 {
+		if raw, err := json.Marshal(meta.Chapters); err == nil {
+			video.Chapters = string(raw)
+		}
+	}
+	if meta.Description != "" {
+		video.Description = meta.Description
+	}
+	video.EnrichedAt = &now
+}
+
+func decodeChapters(raw string) []services.VideoChapter {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var chapters []services.VideoChapter
+	if err := json.Unmarshal([]byte(raw), &chapters); err != nil {
+		return nil
+	}
+	return chapters
+}
+
+func isYouTubeChannel(channel models.Channel) bool {
+	return channel.SourceType == "" || channel.SourceType == services.SourceYouTube
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+func TestUpsertFeedEntryKeepsEnrichedDescription(t *testing.T) {
+	a := newTestAppService(t)
+	video := createTestVideo(t, a, models.Video{VideoID: "dQw4w9WgXcQ", Description: "short"})
+	entry := services.FeedEntry{
+		VideoID:     video.VideoID,
+		Title:       "Video",
+		Description: "short",
+		Metadata:    &services.VideoMetadata{DurationSeconds: 45, Description: "the full description"},
+	}
+
+	if _, _, err := a.upsertFeedEntry(video.ChannelID, entry, time.Now()); err != nil {
+		t.Fatalf("upsert with metadata: %v", err)
+	}
+	var stored models.Video
+	a.DB.Gorm.First(&stored, video.ID)
+	if stored.Description != "the full description" {
+		t.Fatalf("description = %q, want the yt-dlp description", stored.Description)
+	}
+
+	// A later sync without metadata does not shorten it again.
+	entry.Metadata = nil
+	if _, _, err := a.upsertFeedEntry(video.ChannelID, entry, time.Now()); err != nil {
+		t.Fatalf("upsert without metadata: %v", err)
+	}
+	var again models.Video
+	a.DB.Gorm.First(&again, video.ID)
+	if again.Description != "the full description" {
+		t.Fatalf("description = %q after a plain sync", again.Description)
+	}
+}
+
+func TestPlaylistFirstSyncSkipsEnrichment(t *testing.T) {
+	a := newTestAppService(t)
+	setSetting(a.DB, "ytdlp_enabled", "true")
+	setSetting(a.DB, "ytdlp_path", fakeYTDLPMetadata(t, "45"))
+
+	entry := func(id string) string {
+		return `<entry>
+    <id>yt:video:` + id + `</id>
+    <yt:videoId>` + id + `</yt:videoId>
+    <yt:channelId>UCsomeoneelse0123456789a</yt:channelId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=` + id + `"/>
+    <published>2026-10-01T00:00:00+00:00</published>
+  </entry>`
+	}
+	entries := entry("firstVid001")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feeds/videos.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <yt:playlistId>PLtest0123456789</yt:playlistId>
+  <title>Playlist</title>
+  ` + entries + `
+</feed>`))
+	}))
+	t.Cleanup(server.Close)
+	a.YouTube.BaseURL = server.URL
+
+	if _, err := a.syncPlaylistFeed(t.Context(), "PLtest0123456789"); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	var first models.Video
+	a.DB.Gorm.Where("video_id = ?", "firstVid001").First(&first)
+	if first.EnrichedAt != nil {
+		t.Fatal("first playlist sync ran yt-dlp")
+	}
+
+	entries += entry("secondVid01")
+	if _, err := a.syncPlaylistFeed(t.Context(), "PLtest0123456789"); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	var second models.Video
+	a.DB.Gorm.Where("video_id = ?", "secondVid01").First(&second)
+	if second.EnrichedAt == nil || second.DurationSeconds != 45 {
+		t.Fatalf("later sync did not enrich the new video: %+v", second)
+	}
+}
@@ -0,0 +1,222 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+
+	"gorm.io/gorm/clause"
+)
+
+type ChannelGroupInput struct {
+	ID           uint
+	Name         string
+	TemplateName string
+	AutoSummary  bool
+}
+
+type ChannelGroupItem struct {
+	ID           uint
+	Name         string
+	Position     int
+	TemplateName string
+	AutoSummary  bool
+	ChannelCount int64
+}
+
+func (a *AppService) ListChannelGroups() ([]ChannelGroupItem, error) {
+	var groups []models.ChannelGroup
+	if err := a.DB.Gorm.Order("position asc, name asc").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]ChannelGroupItem, 0, len(groups))
+	for _, g := range groups {
+		var count int64
+		if err := a.DB.Gorm.Table("channel_group_members").Where("channel_group_id = ?", g.ID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		items = append(items, ChannelGroupItem{
+			ID:           g.ID,
+			Name:         g.Name,
+			Position:     g.Position,
+			TemplateName: g.TemplateName,
+			AutoSummary:  g.AutoSummary,
+			ChannelCount: count,
+		})
+	}
+	return items, nil
+}
+
+// SaveChannelGroup creates a group when input.ID is 0 and updates it
+// otherwise. New groups are appended after the existing ones.
+func (a *AppService) SaveChannelGroup(input ChannelGroupInput) (models.ChannelGroup, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.ChannelGroup{}, fmt.Errorf("group name is required")
+	}
+	templateName := strings.TrimSpace(input.TemplateName)
+	if templateName != "" {
+		if _, err := a.getTemplateByName(templateName); err != nil {
+			return models.ChannelGroup{}, fmt.Errorf("template %s not found", templateName)
+		}
+	}
+
+	now := time.Now()
+	if input.ID == 0 {
+		var maxPosition int
+		if err := a.DB.Gorm.Model(&models.ChannelGroup{}).Select("coalesce(max(position), 0)").Scan(&maxPosition).Error; err != nil {
+			return models.ChannelGroup{}, err
+		}
+		group := models.ChannelGroup{
+			Name:         name,
+			Position:     maxPosition + 1,
+			TemplateName: templateName,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := a.DB.Gorm.Create(&group).Error; err != nil {
+			return models.ChannelGroup{}, err
+		}
+		input.ID = group.ID
+	}
+
+	// Updated through a map so AutoSummary=false is not skipped as a zero value.
+	if err := a.DB.Gorm.Model(&models.ChannelGroup{}).Where("id = ?", input.ID).Updates(map[string]interface{}{
+		"name":          name,
+		"template_name": templateName,
+		"auto_summary":  input.AutoSummary,
+		"updated_at":    now,
+	}).Error; err != nil {
+		return models.ChannelGroup{}, err
+	}
+
+	var saved models.ChannelGroup
+	if err := a.DB.Gorm.First(&saved, input.ID).Error; err != nil {
+		return models.ChannelGroup{}, err
+	}
+	return saved, nil
+}
+
+func (a *AppService) DeleteChannelGroup(id uint) error {
+	if id == 0 {
+		return fmt.Errorf("group id is required")
+	}
+	if err := a.DB.Gorm.Where("channel_group_id = ?", id).Delete(&models.ChannelGroupMember{}).Error; err != nil {
+		return err
+	}
+	return a.DB.Gorm.Delete(&models.ChannelGroup{}, id).Error
+}
+
+// ReorderChannelGroups stores the order of groupIDs as their positions.
+func (a *AppService) ReorderChannelGroups(groupIDs []uint) error {
+	for i, id := range groupIDs {
+		if err := a.DB.Gorm.Model(&models.ChannelGroup{}).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AppService) AddChannelToGroup(groupID uint, channelID string) error {
+	if groupID == 0 || strings.TrimSpace(channelID) == "" {
+		return fmt.Errorf("groupID and channelID are required")
+	}
+	var channel models.Channel
+	if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&channel).Error; err != nil {
+		return err
+	}
+	var maxPosition int
+	if err := a.DB.Gorm.Model(&models.ChannelGroupMember{}).Where("channel_group_id = ?", groupID).Select("coalesce(max(position), 0)").Scan(&maxPosition).Error; err != nil {
+		return err
+	}
+	link := models.ChannelGroupMember{
+		ChannelGroupID: groupID,
+		ChannelID:      channel.ID,
+		Position:       maxPosition + 1,
+	}
+	return a.DB.Gorm.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error
+}
+
+func (a *AppService) RemoveChannelFromGroup(groupID uint, channelID string) error {
+	if groupID == 0 || strings.TrimSpace(channelID) == "" {
+		return fmt.Errorf("groupID and channelID are required")
+	}
+	var channel models.Channel
+	if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&channel).Error; err != nil {
+		return err
+	}
+	return a.DB.Gorm.Where("channel_group_id = ? AND channel_id = ?", groupID, channel.ID).Delete(&models.ChannelGroupMember{}).Error
+}
+
+// ReorderGroupChannels stores the order of channelIDs as their positions
+// inside the group.
+func (a *AppService) ReorderGroupChannels(groupID uint, channelIDs []string) error {
+	if groupID == 0 {
+		return fmt.Errorf("groupID is required")
+	}
+	for i, channelID := range channelIDs {
+		var channel models.Channel
+		if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&channel).Error; err != nil {
+			return err
+		}
+		if err := a.DB.Gorm.Model(&models.ChannelGroupMember{}).
+			Where("channel_group_id = ? AND channel_id = ?", groupID, channel.ID).
+			Update("position", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AppService) ListGroupChannels(groupID uint) ([]models.Channel, error) {
+	if groupID == 0 {
+		return nil, fmt.Errorf("groupID is required")
+	}
+	var channels []models.Channel
+	if err := a.DB.Gorm.Table("channels").
+		Select("channels.*").
+		Joins("inner join channel_group_members on channel_group_members.channel_id = channels.id").
+		Where("channel_group_members.channel_group_id = ?", groupID).
+		Order("channel_group_members.position asc, channels.name asc").
+		Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// SyncChannelGroup syncs only the channels in one group, skipping paused
+// ones.
+func (a *AppService) SyncChannelGroup(groupID uint) (SyncSummary, error) {
+	members, err := a.ListGroupChannels(groupID)
+	if err != nil {
+		return SyncSummary{}, err
+	}
+	channels := make([]models.Channel, 0, len(members))
+	for _, c := range members {
+		if !c.Paused {
+			channels = append(channels, c)
+		}
+	}
+	run := a.startSyncRun(SyncTriggerManual)
+	summary := a.syncFeeds(channels, nil)
+	a.finishSyncRun(run, summary, nil)
+	return summary, nil
+}
+
+// groupTemplateName returns the default template of the first group, in
+// display order, that sets one for the channel.
+func (a *AppService) groupTemplateName(channelID uint) string {
+	var names []string
+	if err := a.DB.Gorm.Table("channel_groups").
+		Joins("inner join channel_group_members on channel_group_members.channel_group_id = channel_groups.id").
+		Where("channel_group_members.channel_id = ? AND channel_groups.template_name <> ''", channelID).
+		Order("channel_groups.position asc, channel_groups.name asc").
+		Limit(1).
+		Pluck("channel_groups.template_name", &names).Error; err != nil || len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
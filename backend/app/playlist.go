@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlaylistSyncResult struct {
+	PlaylistID    string
+	Title         string
+	NewVideos     int
+	UpdatedVideos int
+	Error         string
+}
+
+type PlaylistItem struct {
+	ID         uint
+	PlaylistID string
+	Title      string
+	Author     string
+	URL        string
+	VideoCount int64
+}
+
+func (a *AppService) ListPlaylists() ([]PlaylistItem, error) {
+	var playlists []models.Playlist
+	if err := a.DB.Gorm.Order("title asc").Find(&playlists).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]PlaylistItem, 0, len(playlists))
+	for _, p := range playlists {
+		var count int64
+		if err := a.DB.Gorm.Table("playlist_videos").Where("playlist_id = ?", p.ID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		items = append(items, PlaylistItem{
+			ID:         p.ID,
+			PlaylistID: p.PlaylistID,
+			Title:      p.Title,
+			Author:     p.Author,
+			URL:        p.URL,
+			VideoCount: count,
+		})
+	}
+	return items, nil
+}
+
+func (a *AppService) SyncPlaylistFeed(playlistID string) (PlaylistSyncResult, error) {
+	return a.syncPlaylistFeed(context.Background(), playlistID)
+}
+
+func (a *AppService) syncPlaylistFeed(ctx context.Context, playlistID string) (PlaylistSyncResult, error) {
+	id := services.ExtractPlaylistID(playlistID)
+	if id == "" {
+		return PlaylistSyncResult{}, fmt.Errorf("invalid playlist id: %s", playlistID)
+	}
+
+	defer a.schedulePlaylistPoll(id)
+
+	feed, err := a.YouTube.FetchPlaylistFeed(ctx, id)
+	if err != nil {
+		return PlaylistSyncResult{}, err
+	}
+	a.classifyNewEntries(ctx, feed.Entries)
+	// Like a channel's first sync, the first sync of a playlist would enrich
+	// the whole feed; those videos are enriched lazily when summarized.
+	var known models.Playlist
+	if err := a.DB.Gorm.Select("last_polled_at").Where("playlist_id = ?", id).First(&known).Error; err == nil && known.LastPolledAt != nil {
+		a.enrichNewEntries(ctx, feed.Entries)
+	}
+	rules := a.loadRules()
+	defer a.cacheThumbnails(ctx, feed.Entries)
+
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+
+	now := time.Now()
+	playlist := models.Playlist{
+		PlaylistID: feed.PlaylistID,
+		Title:      feed.Title,
+		Author:     feed.Author,
+		URL:        feed.URL,
+		UpdatedAt:  now,
+	}
+	if err := a.DB.Gorm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "playlist_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "author", "url", "updated_at"}),
+	}).Create(&playlist).Error; err != nil {
+		return PlaylistSyncResult{}, err
+	}
+
+	var playlistRecord models.Playlist
+	if err := a.DB.Gorm.Where("playlist_id = ?", feed.PlaylistID).First(&playlistRecord).Error; err != nil {
+		return PlaylistSyncResult{}, err
+	}
+
+	newCount := 0
+	updatedCount := 0
+
+	for _, entry := range feed.Entries {
+		// Videos of unsubscribed channels are stored without a channel;
+		// rules scoped to a channel then do not match them.
+		var channel models.Channel
+		if entry.ChannelID != "" {
+			if err := a.DB.Gorm.Where("channel_id = ?", entry.ChannelID).First(&channel).Error; err != nil {
+				channel = models.Channel{}
+			}
+		}
+
+		video, created, err := a.storeFeedEntry(channel, entry, rules, now)
+		if err != nil {
+			return PlaylistSyncResult{}, err
+		}
+		if created {
+			newCount++
+		} else {
+			updatedCount++
+		}
+
+		link := models.PlaylistVideo{
+			PlaylistID: playlistRecord.ID,
+			VideoID:    video.ID,
+		}
+		if err := a.DB.Gorm.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+			return PlaylistSyncResult{}, err
+		}
+	}
+
+	return PlaylistSyncResult{
+		PlaylistID:    feed.PlaylistID,
+		Title:         feed.Title,
+		NewVideos:     newCount,
+		UpdatedVideos: updatedCount,
+	}, nil
+}
+
+func (a *AppService) DeletePlaylist(playlistID string) error {
+	if strings.TrimSpace(playlistID) == "" {
+		return fmt.Errorf("playlistID is required")
+	}
+
+	var playlist models.Playlist
+	if err := a.DB.Gorm.Where("playlist_id = ?", playlistID).First(&playlist).Error; err != nil {
+		return err
+	}
+
+	var orphans []models.Video
+	if err := a.DB.Gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("playlist_id = ?", playlist.ID).Delete(&models.PlaylistVideo{}).Error; err != nil {
+			return err
+		}
+
+		// Videos that arrived only through this playlist have no other owner left.
+		if err := tx.Select("id", "video_id").
+			Where("channel_id = 0 AND id NOT IN (?)", tx.Table("playlist_videos").Select("video_id")).
+			Find(&orphans).Error; err != nil {
+			return err
+		}
+		if err := deleteVideos(tx, orphans); err != nil {
+			return err
+		}
+		return tx.Delete(&playlist).Error
+	}); err != nil {
+		return err
+	}
+	a.removeThumbnails(orphans)
+	return nil
+}
+
+func (a *AppService) ListPlaylistVideos(playlistID string) ([]VideoItem, error) {
+	if strings.TrimSpace(playlistID) == "" {
+		return nil, fmt.Errorf("playlistID is required")
+	}
+	var videos []VideoItem
+	if err := a.DB.Gorm.Table("videos").
+		Select("videos.id, videos.video_id, videos.title, videos.url, videos.channel_id, channels.name as channel_name, videos.thumbnail, videos.summary, videos.published_at").
+		Joins("left join channels on channels.id = videos.channel_id").
+		Joins("inner join playlist_videos on playlist_videos.video_id = videos.id").
+		Joins("inner join playlists on playlists.id = playlist_videos.playlist_id").
+		Where("playlists.playlist_id = ?", playlistID).
+		Order("videos.published_at desc").
+		Scan(&videos).Error; err != nil {
+		return nil, err
+	}
+	a.localThumbnails(videos)
+	return videos, nil
+}
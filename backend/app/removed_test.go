@@ -0,0 +1,69 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+)
+
+func TestSyncFlagsPrivateVideosAsRemoved(t *testing.T) {
+	a := newTestAppService(t)
+	feed := `<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <yt:channelId>` + testChannelID + `</yt:channelId>
+  <title>Test</title>
+  <entry>
+    <id>yt:video:keptVideo01</id>
+    <yt:videoId>keptVideo01</yt:videoId>
+    <yt:channelId>` + testChannelID + `</yt:channelId>
+    <title>Kept</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=keptVideo01"/>
+    <published>2026-10-01T00:00:00+00:00</published>
+  </entry>
+</feed>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feeds/videos.xml":
+			_, _ = w.Write([]byte(feed))
+		case "/oembed":
+			// YouTube answers 401 for videos that were made private.
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	a.YouTube.BaseURL = server.URL
+
+	private := createTestVideo(t, a, models.Video{
+		VideoID:     "privVideo01",
+		VideoType:   "regular",
+		PublishedAt: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+	})
+	older := createTestVideo(t, a, models.Video{
+		VideoID:     "oldVideo001",
+		VideoType:   "regular",
+		PublishedAt: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	result, err := a.syncChannelFeed(t.Context(), testChannelID)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if result.RemovedVideos != 1 {
+		t.Fatalf("RemovedVideos = %d, want 1", result.RemovedVideos)
+	}
+	var stored models.Video
+	a.DB.Gorm.First(&stored, private.ID)
+	if !stored.Removed {
+		t.Fatal("private video not flagged as removed")
+	}
+	// Videos older than the feed window are not checked.
+	var kept models.Video
+	a.DB.Gorm.First(&kept, older.ID)
+	if kept.Removed {
+		t.Fatal("video outside the feed window flagged as removed")
+	}
+}
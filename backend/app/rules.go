@@ -0,0 +1,342 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+
+	"gorm.io/gorm/clause"
+)
+
+type RuleInput struct {
+	ID                 uint
+	Name               string
+	Enabled            bool
+	ChannelID          string
+	TitlePattern       string
+	VideoType          string
+	MinDurationSeconds int
+	MaxDurationSeconds int
+	Mute               bool
+	SkipSummary        bool
+	TagID              uint
+	CollectionID       uint
+	TemplateName       string
+	StopProcessing     bool
+}
+
+type RuleMatch struct {
+	VideoID     string
+	Title       string
+	ChannelName string
+	PublishedAt time.Time
+}
+
+type compiledRule struct {
+	models.Rule
+	title *regexp.Regexp
+}
+
+func (a *AppService) ListRules() ([]models.Rule, error) {
+	var rules []models.Rule
+	if err := a.DB.Gorm.Order("position asc, id asc").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SaveRule creates a rule when input.ID is 0 and updates it otherwise. New
+// rules run after the existing ones.
+func (a *AppService) SaveRule(input RuleInput) (models.Rule, error) {
+	rule, err := a.ruleFromInput(input)
+	if err != nil {
+		return models.Rule{}, err
+	}
+
+	now := time.Now()
+	if input.ID == 0 {
+		var maxPosition int
+		if err := a.DB.Gorm.Model(&models.Rule{}).Select("coalesce(max(position), 0)").Scan(&maxPosition).Error; err != nil {
+			return models.Rule{}, err
+		}
+		created := models.Rule{Name: rule.Name, Position: maxPosition + 1, CreatedAt: now, UpdatedAt: now}
+		if err := a.DB.Gorm.Create(&created).Error; err != nil {
+			return models.Rule{}, err
+		}
+		input.ID = created.ID
+	}
+
+	// Updated through a map so false and zero conditions are not skipped.
+	if err := a.DB.Gorm.Model(&models.Rule{}).Where("id = ?", input.ID).Updates(map[string]interface{}{
+		"name":                 rule.Name,
+		"enabled":              rule.Enabled,
+		"channel_id":           rule.ChannelID,
+		"title_pattern":        rule.TitlePattern,
+		"video_type":           rule.VideoType,
+		"min_duration_seconds": rule.MinDurationSeconds,
+		"max_duration_seconds": rule.MaxDurationSeconds,
+		"mute":                 rule.Mute,
+		"skip_summary":         rule.SkipSummary,
+		"tag_id":               rule.TagID,
+		"collection_id":        rule.CollectionID,
+		"template_name":        rule.TemplateName,
+		"stop_processing":      rule.StopProcessing,
+		"updated_at":           now,
+	}).Error; err != nil {
+		return models.Rule{}, err
+	}
+
+	var saved models.Rule
+	if err := a.DB.Gorm.First(&saved, input.ID).Error; err != nil {
+		return models.Rule{}, err
+	}
+	return saved, nil
+}
+
+func (a *AppService) DeleteRule(id uint) error {
+	if id == 0 {
+		return fmt.Errorf("rule id is required")
+	}
+	return a.DB.Gorm.Delete(&models.Rule{}, id).Error
+}
+
+// ReorderRules stores the order of ruleIDs as their evaluation order.
+func (a *AppService) ReorderRules(ruleIDs []uint) error {
+	for i, id := range ruleIDs {
+		if err := a.DB.Gorm.Model(&models.Rule{}).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DryRunRule evaluates an unsaved rule against the most recent stored videos
+// and returns the ones it would match. Nothing is modified.
+func (a *AppService) DryRunRule(input RuleInput, limit int) ([]RuleMatch, error) {
+	rule, err := a.ruleFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := compileRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 200
+	}
+
+	channels, err := a.ListChannels()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Channel, len(channels))
+	for _, c := range channels {
+		byID[c.ID] = c
+	}
+
+	query := a.DB.Gorm.Model(&models.Video{})
+	if rule.ChannelID != "" {
+		query = query.Where("channel_id IN (?)", a.DB.Gorm.Model(&models.Channel{}).Select("id").Where("channel_id = ?", rule.ChannelID))
+	}
+	var videos []models.Video
+	if err := query.Order("published_at desc").Limit(limit).Find(&videos).Error; err != nil {
+		return nil, err
+	}
+
+	matches := make([]RuleMatch, 0)
+	for _, v := range videos {
+		channel := byID[v.ChannelID]
+		if !compiled.matches(v, channel.ChannelID) {
+			continue
+		}
+		matches = append(matches, RuleMatch{
+			VideoID:     v.VideoID,
+			Title:       v.Title,
+			ChannelName: channel.Name,
+			PublishedAt: v.PublishedAt,
+		})
+	}
+	return matches, nil
+}
+
+func (a *AppService) ruleFromInput(input RuleInput) (models.Rule, error) {
+	rule := models.Rule{
+		ID:                 input.ID,
+		Name:               strings.TrimSpace(input.Name),
+		Enabled:            input.Enabled,
+		ChannelID:          strings.TrimSpace(input.ChannelID),
+		TitlePattern:       strings.TrimSpace(input.TitlePattern),
+		VideoType:          strings.ToLower(strings.TrimSpace(input.VideoType)),
+		MinDurationSeconds: input.MinDurationSeconds,
+		MaxDurationSeconds: input.MaxDurationSeconds,
+		Mute:               input.Mute,
+		SkipSummary:        input.SkipSummary,
+		TagID:              input.TagID,
+		CollectionID:       input.CollectionID,
+		TemplateName:       strings.TrimSpace(input.TemplateName),
+		StopProcessing:     input.StopProcessing,
+	}
+	if rule.Name == "" {
+		return models.Rule{}, fmt.Errorf("rule name is required")
+	}
+	if rule.VideoType != "" && !services.IsValidVideoType(rule.VideoType) {
+		return models.Rule{}, fmt.Errorf("unknown video type: %s", rule.VideoType)
+	}
+	if rule.MinDurationSeconds < 0 || rule.MaxDurationSeconds < 0 {
+		return models.Rule{}, fmt.Errorf("duration must not be negative")
+	}
+	if rule.MaxDurationSeconds > 0 && rule.MinDurationSeconds > rule.MaxDurationSeconds {
+		return models.Rule{}, fmt.Errorf("minimum duration exceeds maximum duration")
+	}
+	if rule.MinDurationSeconds > 0 || rule.MaxDurationSeconds > 0 {
+		if settings, err := a.GetAppSettings(); err == nil && !settings.YTDLPEnabled {
+			return models.Rule{}, fmt.Errorf("duration conditions need yt-dlp enrichment, which is turned off")
+		}
+	}
+	if _, err := compileRule(rule); err != nil {
+		return models.Rule{}, err
+	}
+	if rule.TemplateName != "" {
+		if _, err := a.getTemplateByName(rule.TemplateName); err != nil {
+			return models.Rule{}, fmt.Errorf("template %s not found", rule.TemplateName)
+		}
+	}
+	if rule.TagID != 0 {
+		if err := a.DB.Gorm.First(&models.Tag{}, rule.TagID).Error; err != nil {
+			return models.Rule{}, fmt.Errorf("tag %d not found", rule.TagID)
+		}
+	}
+	if rule.CollectionID != 0 {
+		if err := a.DB.Gorm.First(&models.Collection{}, rule.CollectionID).Error; err != nil {
+			return models.Rule{}, fmt.Errorf("collection %d not found", rule.CollectionID)
+		}
+	}
+	return rule, nil
+}
+
+func compileRule(rule models.Rule) (compiledRule, error) {
+	compiled := compiledRule{Rule: rule}
+	if rule.TitlePattern != "" {
+		re, err := regexp.Compile(rule.TitlePattern)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("invalid title pattern: %w", err)
+		}
+		compiled.title = re
+	}
+	return compiled, nil
+}
+
+// matches reports whether every condition set on the rule holds. Duration
+// conditions never match a video whose duration is not known yet; such
+// videos are checked again once enrichment fills it in.
+func (r compiledRule) matches(video models.Video, channelID string) bool {
+	if r.ChannelID != "" && r.ChannelID != channelID {
+		return false
+	}
+	if r.title != nil && !r.title.MatchString(video.Title) {
+		return false
+	}
+	if r.VideoType != "" && r.VideoType != video.VideoType {
+		return false
+	}
+	if r.hasDuration() {
+		if video.DurationSeconds <= 0 {
+			return false
+		}
+		if r.MinDurationSeconds > 0 && video.DurationSeconds < r.MinDurationSeconds {
+			return false
+		}
+		if r.MaxDurationSeconds > 0 && video.DurationSeconds > r.MaxDurationSeconds {
+			return false
+		}
+	}
+	return true
+}
+
+func (r compiledRule) hasDuration() bool {
+	return r.MinDurationSeconds > 0 || r.MaxDurationSeconds > 0
+}
+
+func (r compiledRule) hasVideoType() bool {
+	return r.VideoType != ""
+}
+
+// loadRules returns the enabled rules in evaluation order. Rules whose
+// pattern no longer compiles are skipped.
+func (a *AppService) loadRules() []compiledRule {
+	var rules []models.Rule
+	if err := a.DB.Gorm.Where("enabled = ?", true).Order("position asc, id asc").Find(&rules).Error; err != nil {
+		return nil
+	}
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		c, err := compileRule(rule)
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Printf("skip rule %d: %v", rule.ID, err)
+			}
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled
+}
+
+// applyRules runs rules against a newly stored video. The first matching
+// rule that picks a template wins. A non-nil only limits which rules take
+// effect, for reruns after enrichment or classification: the rest already
+// ran at ingest, but still count for stop processing and for which template
+// wins.
+func (a *AppService) applyRules(rules []compiledRule, video models.Video, channelID string, only func(compiledRule) bool) error {
+	updates := map[string]interface{}{}
+	templatePicked := false
+	for _, rule := range rules {
+		if !rule.matches(video, channelID) {
+			continue
+		}
+		if only != nil && !only(rule) {
+			if rule.TemplateName != "" {
+				templatePicked = true
+			}
+			if rule.StopProcessing {
+				break
+			}
+			continue
+		}
+		if rule.Mute {
+			updates["muted"] = true
+		}
+		if rule.SkipSummary {
+			updates["skip_summary"] = true
+		}
+		if rule.TemplateName != "" && !templatePicked {
+			updates["template_name"] = rule.TemplateName
+			templatePicked = true
+		}
+		if rule.TagID != 0 {
+			var tag models.Tag
+			if err := a.DB.Gorm.Where("id = ?", rule.TagID).First(&tag).Error; err == nil {
+				if err := a.DB.Gorm.Model(&video).Association("Tags").Append(&tag); err != nil {
+					return err
+				}
+			}
+		}
+		if rule.CollectionID != 0 {
+			link := models.CollectionVideo{CollectionID: rule.CollectionID, VideoID: video.ID}
+			if err := a.DB.Gorm.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+				return err
+			}
+		}
+		if rule.StopProcessing {
+			break
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Updates(updates).Error
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"ytfeedgenerator/backend/models"
+)
+
+// fakeYTDLPMetadata writes a yt-dlp stand-in that reports every video as
+// durationSeconds long.
+func fakeYTDLPMetadata(t *testing.T, durationSeconds string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the yt-dlp stand-in is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "yt-dlp")
+	script := "#!/bin/sh\necho '{\"duration\": " + durationSeconds + "}'\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDurationRulesRunAfterEnrichment(t *testing.T) {
+	a := newTestAppService(t)
+	setSetting(a.DB, "ytdlp_enabled", "true")
+	setSetting(a.DB, "ytdlp_path", fakeYTDLPMetadata(t, "45"))
+
+	if _, err := a.SaveRule(RuleInput{Name: "mute shorts", Enabled: true, MaxDurationSeconds: 60, Mute: true}); err != nil {
+		t.Fatalf("SaveRule: %v", err)
+	}
+	video := createTestVideo(t, a, models.Video{VideoID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"})
+	if err := a.applyRules(a.loadRules(), video, testChannelID, nil); err != nil {
+		t.Fatal(err)
+	}
+	var stored models.Video
+	a.DB.Gorm.First(&stored, video.ID)
+	if stored.Muted {
+		t.Fatal("duration rule matched a video of unknown duration")
+	}
+
+	enriched, err := a.enrichStoredVideo(t.Context(), stored)
+	if err != nil {
+		t.Fatalf("enrichStoredVideo: %v", err)
+	}
+	if enriched.DurationSeconds != 45 || !enriched.Muted {
+		t.Fatalf("after enrichment: duration=%d muted=%v, want 45 and muted", enriched.DurationSeconds, enriched.Muted)
+	}
+}
+
+func TestDurationRulesNeedEnrichment(t *testing.T) {
+	a := newTestAppService(t)
+	_, err := a.SaveRule(RuleInput{Name: "long", Enabled: true, MinDurationSeconds: 3600})
+	if err == nil || !strings.Contains(err.Error(), "yt-dlp") {
+		t.Fatalf("SaveRule with yt-dlp off: err = %v", err)
+	}
+}
+
+func TestPlaylistSyncAppliesRules(t *testing.T) {
+	a := newTestAppService(t)
+	feed := `<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <yt:playlistId>PLtest0123456789</yt:playlistId>
+  <title>Playlist</title>
+  <entry>
+    <id>yt:video:liveVideo01</id>
+    <yt:channelId>UCsomeoneelse0123456789a</yt:channelId>
+    <title>Live: late show</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=liveVideo01"/>
+    <published>2026-10-01T00:00:00+00:00</published>
+  </entry>
+</feed>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feeds/videos.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(feed))
+	}))
+	t.Cleanup(server.Close)
+	a.YouTube.BaseURL = server.URL
+
+	if _, err := a.SaveRule(RuleInput{Name: "mute live", Enabled: true, TitlePattern: "^Live:", Mute: true}); err != nil {
+		t.Fatalf("SaveRule: %v", err)
+	}
+	result, err := a.syncPlaylistFeed(t.Context(), "PLtest0123456789")
+	if err != nil || result.NewVideos != 1 {
+		t.Fatalf("syncPlaylistFeed = %+v, %v", result, err)
+	}
+	var video models.Video
+	if err := a.DB.Gorm.Where("video_id = ?", "liveVideo01").First(&video).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !video.Muted {
+		t.Fatal("playlist ingest skipped the rules")
+	}
+}
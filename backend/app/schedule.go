@@ -0,0 +1,224 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+)
+
+// A channel that uploads once every referenceUploadGap is polled at the
+// global interval; more frequent uploaders are polled more often, down to
+// minPollInterval, and rarer ones less often, up to maxPollInterval.
+const (
+	minPollInterval    = 15 * time.Minute
+	maxPollInterval    = 24 * time.Hour
+	referenceUploadGap = 7 * 24 * time.Hour
+	dormantAfter       = 60 * 24 * time.Hour
+	pollSampleUploads  = 10
+)
+
+type ChannelSchedule struct {
+	ChannelID       string
+	Name            string
+	IntervalMinutes int
+	OverrideMinutes int
+	LastPolledAt    *time.Time
+	NextPollAt      *time.Time
+}
+
+func (a *AppService) ListChannelSchedules() ([]ChannelSchedule, error) {
+	var channels []models.Channel
+	if err := a.DB.Gorm.Order("next_poll_at asc").Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	items := make([]ChannelSchedule, 0, len(channels))
+	for _, c := range channels {
+		items = append(items, ChannelSchedule{
+			ChannelID:       c.ChannelID,
+			Name:            c.Name,
+			IntervalMinutes: c.PollIntervalMinutes,
+			OverrideMinutes: c.PollOverrideMinutes,
+			LastPolledAt:    c.LastPolledAt,
+			NextPollAt:      c.NextPollAt,
+		})
+	}
+	return items, nil
+}
+
+// SetChannelPollInterval pins a channel to a fixed interval; 0 restores the
+// interval derived from its upload frequency.
+func (a *AppService) SetChannelPollInterval(channelID string, minutes int) (ChannelSchedule, error) {
+	if strings.TrimSpace(channelID) == "" {
+		return ChannelSchedule{}, fmt.Errorf("channelID is required")
+	}
+	if minutes < 0 {
+		minutes = 0
+	}
+	if minutes > 0 && minutes < 5 {
+		minutes = 5
+	}
+	if minutes > 7*24*60 {
+		minutes = 7 * 24 * 60
+	}
+
+	a.syncWriteMu.Lock()
+	err := a.DB.Gorm.Model(&models.Channel{}).
+		Where("channel_id = ?", channelID).
+		Update("poll_override_minutes", minutes).Error
+	a.syncWriteMu.Unlock()
+	if err != nil {
+		return ChannelSchedule{}, err
+	}
+
+	a.scheduleChannelPoll(channelID, false)
+
+	var channel models.Channel
+	if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&channel).Error; err != nil {
+		return ChannelSchedule{}, err
+	}
+	return ChannelSchedule{
+		ChannelID:       channel.ChannelID,
+		Name:            channel.Name,
+		IntervalMinutes: channel.PollIntervalMinutes,
+		OverrideMinutes: channel.PollOverrideMinutes,
+		LastPolledAt:    channel.LastPolledAt,
+		NextPollAt:      channel.NextPollAt,
+	}, nil
+}
+
+// SyncDueChannels syncs only the channels and playlists whose next poll time
+// has passed. The background scheduler calls it on a short tick.
+func (a *AppService) SyncDueChannels() (SyncSummary, error) {
+	now := time.Now()
+
+	var channels []models.Channel
+	if err := a.DB.Gorm.Where("paused = ? AND (next_poll_at IS NULL OR next_poll_at <= ?)", false, now).Order("next_poll_at asc").Find(&channels).Error; err != nil {
+		return SyncSummary{}, err
+	}
+
+	var due []models.Playlist
+	if err := a.DB.Gorm.Where("next_poll_at IS NULL OR next_poll_at <= ?", now).Find(&due).Error; err != nil {
+		return SyncSummary{}, err
+	}
+	playlists := make([]PlaylistItem, 0, len(due))
+	for _, p := range due {
+		playlists = append(playlists, PlaylistItem{ID: p.ID, PlaylistID: p.PlaylistID, Title: p.Title})
+	}
+
+	// Idle ticks are not worth a history entry.
+	if len(channels) == 0 && len(playlists) == 0 {
+		return SyncSummary{}, nil
+	}
+
+	run := a.startSyncRun(SyncTriggerScheduler)
+	summary := a.syncFeeds(channels, playlists)
+	a.finishSyncRun(run, summary, nil)
+	return summary, nil
+}
+
+// scheduleChannelPoll sets a channel's next poll time. After a poll the
+// interval counts from now; otherwise, e.g. after an override changed, it
+// counts from the last poll.
+func (a *AppService) scheduleChannelPoll(channelID string, polled bool) {
+	var channel models.Channel
+	if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&channel).Error; err != nil {
+		return
+	}
+
+	base := a.basePollInterval()
+	interval := time.Duration(channel.PollOverrideMinutes) * time.Minute
+	if interval <= 0 {
+		var published []time.Time
+		if err := a.DB.Gorm.Model(&models.Video{}).
+			Where("channel_id = ?", channel.ID).
+			Order("published_at desc").
+			Limit(pollSampleUploads).
+			Pluck("published_at", &published).Error; err != nil {
+			published = nil
+		}
+		interval = computePollInterval(published, base, time.Now())
+	}
+
+	now := time.Now()
+	next := now.Add(interval)
+	if !polled && channel.LastPolledAt != nil {
+		next = channel.LastPolledAt.Add(interval)
+	}
+	updates := map[string]interface{}{
+		"poll_interval_minutes": int(interval / time.Minute),
+		"next_poll_at":          &next,
+	}
+	if polled {
+		updates["last_polled_at"] = &now
+	}
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+	if err := a.DB.Gorm.Model(&models.Channel{}).Where("id = ?", channel.ID).Updates(updates).Error; err != nil && a.logger != nil {
+		a.logger.Printf("schedule channel poll failed: %s: %v", channelID, err)
+	}
+}
+
+func (a *AppService) schedulePlaylistPoll(playlistID string) {
+	now := time.Now()
+	next := now.Add(a.basePollInterval())
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+	if err := a.DB.Gorm.Model(&models.Playlist{}).Where("playlist_id = ?", playlistID).Updates(map[string]interface{}{
+		"last_polled_at": &now,
+		"next_poll_at":   &next,
+	}).Error; err != nil && a.logger != nil {
+		a.logger.Printf("schedule playlist poll failed: %s: %v", playlistID, err)
+	}
+}
+
+func (a *AppService) basePollInterval() time.Duration {
+	base := time.Duration(a.GetSyncSettings().IntervalMinutes) * time.Minute
+	if base < time.Minute {
+		base = time.Minute
+	}
+	return base
+}
+
+// computePollInterval scales the global interval by the channel's median
+// gap between uploads relative to referenceUploadGap. Channels that have not
+// uploaded in a long while are polled once a day.
+func computePollInterval(published []time.Time, base time.Duration, now time.Time) time.Duration {
+	if base > maxPollInterval {
+		base = maxPollInterval
+	}
+	if len(published) == 0 {
+		return base
+	}
+
+	sort.Slice(published, func(i, j int) bool { return published[i].After(published[j]) })
+	if now.Sub(published[0]) > dormantAfter {
+		return maxPollInterval
+	}
+	if len(published) < 2 {
+		return base
+	}
+
+	gaps := make([]time.Duration, 0, len(published)-1)
+	for i := 1; i < len(published); i++ {
+		gaps = append(gaps, published[i-1].Sub(published[i]))
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	median := gaps[len(gaps)/2]
+
+	// Multiplied as floats since base times a long gap overflows a Duration.
+	interval := time.Duration(float64(base) * float64(median) / float64(referenceUploadGap))
+	floor := minPollInterval
+	if base < floor {
+		floor = base
+	}
+	if interval < floor {
+		interval = floor
+	}
+	if interval > maxPollInterval {
+		interval = maxPollInterval
+	}
+	return interval
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+)
+
+// uploadsEvery returns n upload times gap apart, the newest at now.
+func uploadsEvery(now time.Time, gap time.Duration, n int) []time.Time {
+	published := make([]time.Time, 0, n)
+	for i := 0; i < n; i++ {
+		published = append(published, now.Add(-time.Duration(i)*gap))
+	}
+	return published
+}
+
+func TestComputePollInterval(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	base := 30 * time.Minute
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		published []time.Time
+		want      time.Duration
+	}{
+		{"no uploads", nil, base},
+		{"single upload", uploadsEvery(now, day, 1), base},
+		{"several a day", uploadsEvery(now, 4*time.Hour, 10), minPollInterval},
+		{"daily", uploadsEvery(now, day, 10), minPollInterval},
+		{"weekly", uploadsEvery(now, 7*day, 8), base},
+		{"monthly", uploadsEvery(now, 28*day, 2), 4 * base},
+		{"dormant", uploadsEvery(now.Add(-90*day), day, 10), maxPollInterval},
+	}
+	for _, tt := range tests {
+		if got := computePollInterval(tt.published, base, now); got != tt.want {
+			t.Errorf("%s: computePollInterval = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// A daily uploader is never polled less often than the global interval.
+	if got := computePollInterval(uploadsEvery(now, day, 10), 2*time.Hour, now); got >= 2*time.Hour {
+		t.Errorf("daily uploader with a 2h base polled every %v", got)
+	}
+	// The floor never exceeds a shorter global interval.
+	if got := computePollInterval(uploadsEvery(now, day, 10), 5*time.Minute, now); got != 5*time.Minute {
+		t.Errorf("daily uploader with a 5m base polled every %v", got)
+	}
+}
+
+func TestSetChannelPollIntervalKeepsLastPoll(t *testing.T) {
+	a := newTestAppService(t)
+	polled := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	channel := models.Channel{ChannelID: testChannelID, Name: "Test", LastPolledAt: &polled}
+	if err := a.DB.Gorm.Create(&channel).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	schedule, err := a.SetChannelPollInterval(testChannelID, 60)
+	if err != nil {
+		t.Fatalf("SetChannelPollInterval: %v", err)
+	}
+	if schedule.LastPolledAt == nil || !schedule.LastPolledAt.Equal(polled) {
+		t.Fatalf("last poll = %v, want it untouched at %v", schedule.LastPolledAt, polled)
+	}
+	if want := polled.Add(time.Hour); schedule.NextPollAt == nil || !schedule.NextPollAt.Equal(want) {
+		t.Fatalf("next poll = %v, want %v", schedule.NextPollAt, want)
+	}
+}
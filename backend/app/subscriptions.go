@@ -0,0 +1,399 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+
+	"gorm.io/gorm/clause"
+)
+
+type SubscriptionImportReport struct {
+	Imported   int
+	Duplicates int
+	Invalid    int
+	Groups     int
+	NewIDs     []string
+	Errors     []string
+}
+
+func (a *AppService) ImportOPML(path string) (SubscriptionImportReport, error) {
+	if strings.TrimSpace(path) == "" {
+		return SubscriptionImportReport{}, fmt.Errorf("opml path is required")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return SubscriptionImportReport{}, err
+	}
+	defer file.Close()
+
+	feeds, err := a.OPML.Parse(file)
+	if err != nil {
+		return SubscriptionImportReport{}, err
+	}
+
+	report := SubscriptionImportReport{}
+	// A feed listed again in the same folder is a duplicate; in another
+	// folder it only adds the group membership.
+	type feedKey struct{ channelID, folder string }
+	listed := map[feedKey]bool{}
+	seen := map[string]bool{}
+	groups := map[string]bool{}
+	for _, feed := range feeds {
+		if playlistID := services.PlaylistIDFromFeedURL(feed.XMLURL); playlistID != "" {
+			if seen[playlistID] {
+				report.Duplicates++
+				continue
+			}
+			seen[playlistID] = true
+			created, err := a.ensurePlaylist(playlistID, feed.Title)
+			if err != nil {
+				report.Invalid++
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", playlistID, err))
+			} else if created {
+				report.Imported++
+			} else {
+				report.Duplicates++
+			}
+			continue
+		}
+
+		sourceType := services.SourceYouTube
+		channelID := services.ChannelIDFromFeedURL(feed.XMLURL)
+		if channelID == "" {
+			if !isGenericFeedURL(feed.XMLURL) {
+				report.Invalid++
+				report.Errors = append(report.Errors, fmt.Sprintf("unsupported feed url: %s", feed.XMLURL))
+				continue
+			}
+			// Anything else is kept as a generic RSS/Atom subscription.
+			resolved, err := a.FeedSources[services.SourceRSS].Resolve(context.Background(), feed.XMLURL)
+			if err != nil {
+				report.Invalid++
+				report.Errors = append(report.Errors, fmt.Sprintf("invalid feed url: %s", feed.XMLURL))
+				continue
+			}
+			sourceType = services.SourceRSS
+			channelID = resolved
+		}
+		folder := strings.TrimSpace(feed.Folder)
+		key := feedKey{channelID, folder}
+		if listed[key] {
+			report.Duplicates++
+			continue
+		}
+		listed[key] = true
+
+		channel, created, err := a.ensureChannel(sourceType, channelID, feed.Title)
+		if err != nil {
+			report.Invalid++
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", channelID, err))
+			continue
+		}
+		if !seen[channelID] {
+			seen[channelID] = true
+			if created {
+				report.Imported++
+				report.NewIDs = append(report.NewIDs, channelID)
+			} else {
+				report.Duplicates++
+			}
+		}
+
+		if folder != "" {
+			if err := a.addChannelToGroupName(folder, channel.ID); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: group %s: %v", channelID, folder, err))
+				continue
+			}
+			groups[folder] = true
+		}
+	}
+	report.Groups = len(groups)
+	return report, nil
+}
+
+func (a *AppService) ExportOPML() (string, error) {
+	channels, err := a.ListChannels()
+	if err != nil {
+		return "", err
+	}
+
+	var groups []models.ChannelGroup
+	if err := a.DB.Gorm.Preload("Channels").Order("position asc, name asc").Find(&groups).Error; err != nil {
+		return "", err
+	}
+
+	grouped := map[uint]bool{}
+	folders := make([]services.OPMLFolder, 0, len(groups))
+	for _, g := range groups {
+		folder := services.OPMLFolder{Name: g.Name}
+		for _, c := range g.Channels {
+			grouped[c.ID] = true
+			folder.Feeds = append(folder.Feeds, channelOPMLFeed(c))
+		}
+		folders = append(folders, folder)
+	}
+
+	ungrouped := make([]services.OPMLFeed, 0, len(channels))
+	for _, c := range channels {
+		if grouped[c.ID] {
+			continue
+		}
+		ungrouped = append(ungrouped, channelOPMLFeed(c))
+	}
+
+	var playlists []models.Playlist
+	if err := a.DB.Gorm.Order("title asc").Find(&playlists).Error; err != nil {
+		return "", err
+	}
+	for _, p := range playlists {
+		ungrouped = append(ungrouped, playlistOPMLFeed(p))
+	}
+
+	raw, err := a.OPML.Build("YTFeedGenerator subscriptions", ungrouped, folders)
+	if err != nil {
+		return "", err
+	}
+	return a.Export.ExportOPML(context.Background(), string(raw), "exports", "")
+}
+
+func channelOPMLFeed(c models.Channel) services.OPMLFeed {
+	if c.SourceType == services.SourceRSS {
+		return services.OPMLFeed{Title: c.Name, XMLURL: c.ChannelID, HTMLURL: c.URL}
+	}
+	htmlURL := c.URL
+	if htmlURL == "" {
+		htmlURL = fmt.Sprintf("https://www.youtube.com/channel/%s", c.ChannelID)
+	}
+	return services.OPMLFeed{
+		Title:   c.Name,
+		XMLURL:  fmt.Sprintf("https://www.youtube.com/feeds/videos.xml?channel_id=%s", c.ChannelID),
+		HTMLURL: htmlURL,
+	}
+}
+
+func playlistOPMLFeed(p models.Playlist) services.OPMLFeed {
+	htmlURL := p.URL
+	if htmlURL == "" {
+		htmlURL = fmt.Sprintf("https://www.youtube.com/playlist?list=%s", p.PlaylistID)
+	}
+	return services.OPMLFeed{
+		Title:   p.Title,
+		XMLURL:  fmt.Sprintf("https://www.youtube.com/feeds/videos.xml?playlist_id=%s", p.PlaylistID),
+		HTMLURL: htmlURL,
+	}
+}
+
+// ensureChannel creates a channel row without fetching its feed; the
+// scheduler picks it up because it has no next poll time yet.
+func (a *AppService) ensureChannel(sourceType string, channelID string, name string) (models.Channel, bool, error) {
+	if sourceType == services.SourceYouTube && !services.IsChannelID(channelID) {
+		return models.Channel{}, false, fmt.Errorf("invalid channel id: %s", channelID)
+	}
+
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+
+	var existing models.Channel
+	if err := a.DB.Gorm.Where("channel_id = ?", channelID).First(&existing).Error; err == nil {
+		return existing, false, nil
+	}
+
+	now := time.Now()
+	channel := models.Channel{
+		ChannelID:  channelID,
+		SourceType: sourceType,
+		Name:       strings.TrimSpace(name),
+		URL:        fmt.Sprintf("https://www.youtube.com/channel/%s", channelID),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if sourceType != services.SourceYouTube {
+		channel.URL = channelID
+	}
+	if err := a.DB.Gorm.Create(&channel).Error; err != nil {
+		return models.Channel{}, false, err
+	}
+	return channel, true, nil
+}
+
+// ensurePlaylist creates a playlist row without fetching its feed; like
+// imported channels it is synced on the next scheduler tick.
+func (a *AppService) ensurePlaylist(playlistID string, title string) (bool, error) {
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+
+	now := time.Now()
+	playlist := models.Playlist{
+		PlaylistID: playlistID,
+		Title:      strings.TrimSpace(title),
+		URL:        fmt.Sprintf("https://www.youtube.com/playlist?list=%s", playlistID),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	tx := a.DB.Gorm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "playlist_id"}},
+		DoNothing: true,
+	}).Create(&playlist)
+	return tx.RowsAffected > 0, tx.Error
+}
+
+// isGenericFeedURL accepts http(s) URLs outside YouTube. YouTube feeds other
+// than channel and playlist feeds are not supported.
+func isGenericFeedURL(feedURL string) bool {
+	feedURL = strings.TrimSpace(feedURL)
+	if !strings.HasPrefix(feedURL, "http://") && !strings.HasPrefix(feedURL, "https://") {
+		return false
+	}
+	return !services.IsYouTubeURL(feedURL)
+}
+
+func (a *AppService) addChannelToGroupName(name string, channelID uint) error {
+	now := time.Now()
+	group := models.ChannelGroup{
+		Name:      strings.TrimSpace(name),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := a.DB.Gorm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&group).Error; err != nil {
+		return err
+	}
+	var saved models.ChannelGroup
+	if err := a.DB.Gorm.Where("name = ?", group.Name).First(&saved).Error; err != nil {
+		return err
+	}
+	link := models.ChannelGroupMember{
+		ChannelGroupID: saved.ID,
+		ChannelID:      channelID,
+	}
+	return a.DB.Gorm.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error
+}
+
+// ImportProgress reports the initial syncs of an import. Current is the
+// channel that finished last.
+type ImportProgress struct {
+	Running bool
+	Total   int
+	Done    int
+	Failed  int
+	Current string
+}
+
+// ImportSubscriptions reads a Google Takeout subscriptions.csv, a NewPipe
+// subscriptions.json or a FreeTube profile .db export. When syncNew is set,
+// each newly added channel is synced once; progress is exposed through
+// GetImportProgress while that runs.
+func (a *AppService) ImportSubscriptions(path string, format string, syncNew bool) (SubscriptionImportReport, error) {
+	if strings.TrimSpace(path) == "" {
+		return SubscriptionImportReport{}, fmt.Errorf("subscription file path is required")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return SubscriptionImportReport{}, err
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = a.SubscriptionImport.DetectFormat(path, raw)
+	}
+	subs, invalid, err := a.SubscriptionImport.Parse(format, raw)
+	if err != nil {
+		return SubscriptionImportReport{}, err
+	}
+
+	report := SubscriptionImportReport{Invalid: invalid}
+
+	// A channel listed again under the same group is a duplicate; under
+	// another group, as FreeTube profiles do, it only adds the membership.
+	type subscriptionKey struct{ channelID, group string }
+	listed := map[subscriptionKey]bool{}
+	unique := make([]services.ImportedSubscription, 0, len(subs))
+	for _, sub := range subs {
+		key := subscriptionKey{sub.ChannelID, sub.Group}
+		if listed[key] {
+			report.Duplicates++
+			continue
+		}
+		listed[key] = true
+		unique = append(unique, sub)
+	}
+
+	seen := map[string]bool{}
+	groups := map[string]bool{}
+	for _, sub := range unique {
+		channel, created, err := a.ensureChannel(services.SourceYouTube, sub.ChannelID, sub.Name)
+		if err != nil {
+			report.Invalid++
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", sub.ChannelID, err))
+			continue
+		}
+		if !seen[sub.ChannelID] {
+			seen[sub.ChannelID] = true
+			if created {
+				report.Imported++
+				report.NewIDs = append(report.NewIDs, sub.ChannelID)
+			} else {
+				report.Duplicates++
+			}
+		}
+
+		if sub.Group != "" {
+			if err := a.addChannelToGroupName(sub.Group, channel.ID); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: group %s: %v", sub.ChannelID, sub.Group, err))
+				continue
+			}
+			groups[sub.Group] = true
+		}
+	}
+	report.Groups = len(groups)
+
+	if syncNew && len(report.NewIDs) > 0 {
+		a.syncImportedChannels(report.NewIDs)
+	}
+	return report, nil
+}
+
+func (a *AppService) GetImportProgress() ImportProgress {
+	a.importMu.Lock()
+	defer a.importMu.Unlock()
+	return a.importProgress
+}
+
+func (a *AppService) syncImportedChannels(channelIDs []string) {
+	a.importMu.Lock()
+	a.importProgress = ImportProgress{Running: true, Total: len(channelIDs)}
+	a.importMu.Unlock()
+
+	settings := a.GetSyncSettings()
+	timeout := time.Duration(settings.ChannelTimeoutSeconds) * time.Second
+
+	runBounded(settings.Concurrency, len(channelIDs), func(i int) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_, err := a.syncChannelFeed(ctx, channelIDs[i])
+
+		a.importMu.Lock()
+		a.importProgress.Done++
+		a.importProgress.Current = channelIDs[i]
+		if err != nil {
+			a.importProgress.Failed++
+			if a.logger != nil {
+				a.logger.Printf("initial sync failed: %s: %v", channelIDs[i], err)
+			}
+		}
+		a.importMu.Unlock()
+	})
+
+	a.importMu.Lock()
+	a.importProgress.Running = false
+	a.importProgress.Current = ""
+	a.importMu.Unlock()
+}
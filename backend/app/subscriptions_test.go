@@ -0,0 +1,174 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ytfeedgenerator/backend/models"
+)
+
+func TestImportOPMLSortsFeedKinds(t *testing.T) {
+	a := newTestAppService(t)
+	opml := `<?xml version="1.0"?>
+<opml version="1.0"><body>
+<outline text="Subs">
+  <outline type="rss" text="Channel" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=` + testChannelID + `"/>
+  <outline type="rss" text="Playlist" xmlUrl="https://www.youtube.com/feeds/videos.xml?playlist_id=PLabcdefghijklmnopqrstuvwxyz012345"/>
+  <outline type="rss" text="Playlist again" xmlUrl="https://www.youtube.com/feeds/videos.xml?playlist_id=PLabcdefghijklmnopqrstuvwxyz012345"/>
+  <outline type="rss" text="Blog" xmlUrl="https://blog.example.com/feed.xml"/>
+  <outline type="rss" text="Unsupported" xmlUrl="https://www.youtube.com/feeds/videos.xml?user=someone"/>
+  <outline type="rss" text="Not http" xmlUrl="ftp://example.com/feed.xml"/>
+</outline>
+</body></opml>`
+	path := filepath.Join(t.TempDir(), "subs.opml")
+	if err := os.WriteFile(path, []byte(opml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := a.ImportOPML(path)
+	if err != nil {
+		t.Fatalf("ImportOPML: %v", err)
+	}
+	if report.Imported != 3 || report.Duplicates != 1 || report.Invalid != 2 {
+		t.Fatalf("report = %+v, want 3 imported, 1 duplicate, 2 invalid", report)
+	}
+
+	var playlist models.Playlist
+	if err := a.DB.Gorm.Where("playlist_id = ?", "PLabcdefghijklmnopqrstuvwxyz012345").First(&playlist).Error; err != nil {
+		t.Fatalf("playlist not created: %v", err)
+	}
+	var channels int64
+	a.DB.Gorm.Model(&models.Channel{}).Count(&channels)
+	if channels != 2 {
+		t.Fatalf("%d channels created, want the youtube channel and the blog", channels)
+	}
+
+	// Importing the same file again only reports duplicates.
+	report, err = a.ImportOPML(path)
+	if err != nil {
+		t.Fatalf("second ImportOPML: %v", err)
+	}
+	if report.Imported != 0 || report.Duplicates != 4 {
+		t.Fatalf("second report = %+v", report)
+	}
+}
+
+func TestImportOPMLKeepsFeedsInEveryFolder(t *testing.T) {
+	a := newTestAppService(t)
+	feedURL := "https://www.youtube.com/feeds/videos.xml?channel_id=" + testChannelID
+	opml := `<?xml version="1.0"?>
+<opml version="1.0"><body>
+<outline text="Music">
+  <outline type="rss" text="Channel" xmlUrl="` + feedURL + `"/>
+  <outline type="rss" text="Channel" xmlUrl="` + feedURL + `"/>
+</outline>
+<outline text="Tech">
+  <outline type="rss" text="Channel" xmlUrl="` + feedURL + `"/>
+</outline>
+</body></opml>`
+	path := filepath.Join(t.TempDir(), "subs.opml")
+	if err := os.WriteFile(path, []byte(opml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := a.ImportOPML(path)
+	if err != nil {
+		t.Fatalf("ImportOPML: %v", err)
+	}
+	if report.Imported != 1 || report.Duplicates != 1 || report.Groups != 2 {
+		t.Fatalf("report = %+v, want 1 imported, 1 duplicate, 2 groups", report)
+	}
+	var members int64
+	a.DB.Gorm.Model(&models.ChannelGroupMember{}).Count(&members)
+	if members != 2 {
+		t.Fatalf("channel joined %d groups, want both folders", members)
+	}
+}
+
+func TestExportOPMLRoundTripsPlaylists(t *testing.T) {
+	a := newTestAppService(t)
+	if err := a.DB.Gorm.Create(&models.Channel{ChannelID: testChannelID, Name: "Test", SourceType: "youtube"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ensurePlaylist("PLabcdefghijklmnopqrstuvwxyz012345", "Playlist"); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := a.ExportOPML()
+	if err != nil {
+		t.Fatalf("ExportOPML: %v", err)
+	}
+	// The second app service moves to another working directory.
+	path, err = filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := newTestAppService(t)
+	report, err := b.ImportOPML(path)
+	if err != nil {
+		t.Fatalf("ImportOPML: %v", err)
+	}
+	if report.Imported != 2 || report.Invalid != 0 {
+		t.Fatalf("report = %+v, want the channel and the playlist imported", report)
+	}
+	var playlist models.Playlist
+	if err := b.DB.Gorm.Where("playlist_id = ?", "PLabcdefghijklmnopqrstuvwxyz012345").First(&playlist).Error; err != nil {
+		t.Fatalf("playlist lost in the round trip: %v", err)
+	}
+}
+
+func TestImportSubscriptionsCountsDuplicatesAndInvalidLines(t *testing.T) {
+	const otherChannelID = "UCzyxwvutsrqponmlkjihgfe"
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		imported int
+		dupes    int
+		invalid  int
+	}{
+		{
+			name: "takeout",
+			file: "subscriptions.csv",
+			content: "Channel Id,Channel Url,Channel Title\n" +
+				testChannelID + ",http://www.youtube.com/channel/" + testChannelID + ",Test\n" +
+				testChannelID + ",http://www.youtube.com/channel/" + testChannelID + ",Test\n" +
+				otherChannelID + ",http://www.youtube.com/channel/" + otherChannelID + ",Other\n",
+			imported: 2,
+			dupes:    1,
+		},
+		{
+			// A channel in "All Channels" and in a group profile is listed
+			// twice without being a duplicate.
+			name: "freetube",
+			file: "profiles.db",
+			content: `{"_id":"allChannels","name":"All Channels","subscriptions":[{"id":"` + testChannelID + `","name":"Test"},{"id":"` + otherChannelID + `","name":"Other"}]}` + "\n" +
+				`{"_id":"music","name":"Music","subscriptions":[{"id":"` + testChannelID + `","name":"Test"},{"id":"` + testChannelID + `","name":"Test"}]}` + "\n" +
+				`{"_id":"broken","name":` + "\n",
+			imported: 2,
+			dupes:    1,
+			invalid:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAppService(t)
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			report, err := a.ImportSubscriptions(path, "", false)
+			if err != nil {
+				t.Fatalf("ImportSubscriptions: %v", err)
+			}
+			if report.Imported != tt.imported || report.Duplicates != tt.dupes || report.Invalid != tt.invalid {
+				t.Fatalf("report = %+v, want %d imported, %d duplicates, %d invalid", report, tt.imported, tt.dupes, tt.invalid)
+			}
+			if len(report.NewIDs) != tt.imported {
+				t.Fatalf("NewIDs = %v", report.NewIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"ytfeedgenerator/backend/models"
+)
+
+func TestFailedSyncKeepsOldValidators(t *testing.T) {
+	a := newTestAppService(t)
+	feed := `<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <yt:channelId>` + testChannelID + `</yt:channelId>
+  <title>Test</title>
+  <entry>
+    <id>yt:video:brokenVid01</id>
+    <yt:videoId>brokenVid01</yt:videoId>
+    <yt:channelId>` + testChannelID + `</yt:channelId>
+    <title>Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=brokenVid01"/>
+    <published>2026-10-01T00:00:00+00:00</published>
+  </entry>
+</feed>`
+	var mu sync.Mutex
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feeds/videos.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		sent = append(sent, r.Header.Get("If-None-Match"))
+		mu.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(feed))
+	}))
+	t.Cleanup(server.Close)
+	a.YouTube.BaseURL = server.URL
+
+	if err := a.DB.Gorm.Create(&models.Channel{ChannelID: testChannelID, Name: "Test", ETag: `"v0"`}).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Storing the entry fails once.
+	if err := a.DB.Gorm.Exec(`CREATE TRIGGER fail_video BEFORE INSERT ON videos BEGIN SELECT RAISE(ABORT, 'disk full'); END`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.syncChannelFeed(t.Context(), testChannelID); err == nil {
+		t.Fatal("sync succeeded although the entry store failed")
+	}
+	if err := a.DB.Gorm.Exec(`DROP TRIGGER fail_video`).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := a.syncChannelFeed(t.Context(), testChannelID)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if len(sent) != 2 || sent[1] != `"v0"` {
+		t.Fatalf("validators sent = %q, want the old one on the retry", sent)
+	}
+	if result.NewVideos != 1 {
+		t.Fatalf("retry stored %d new videos, want 1", result.NewVideos)
+	}
+	var channel models.Channel
+	a.DB.Gorm.Where("channel_id = ?", testChannelID).First(&channel)
+	if channel.ETag != `"v1"` {
+		t.Fatalf("ETag = %q after a successful sync", channel.ETag)
+	}
+}
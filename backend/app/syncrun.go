@@ -0,0 +1,114 @@
+package app
+
+import (
+	"time"
+
+	"ytfeedgenerator/backend/models"
+)
+
+const (
+	SyncTriggerManual    = "manual"
+	SyncTriggerTray      = "tray"
+	SyncTriggerScheduler = "scheduler"
+)
+
+const syncRunRetention = 30 * 24 * time.Hour
+
+// ListSyncRuns returns the most recent sync runs with their per-channel
+// results, newest first.
+func (a *AppService) ListSyncRuns(limit int) ([]models.SyncRun, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	var runs []models.SyncRun
+	if err := a.DB.Gorm.Preload("Results").Order("started_at desc").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// startSyncRun stores the run before any feed is polled, so a run that never
+// finishes still shows up with an empty FinishedAt.
+func (a *AppService) startSyncRun(trigger string) *models.SyncRun {
+	switch trigger {
+	case SyncTriggerManual, SyncTriggerTray, SyncTriggerScheduler:
+	default:
+		trigger = SyncTriggerManual
+	}
+	run := &models.SyncRun{Trigger: trigger, StartedAt: time.Now()}
+
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+	if err := a.DB.Gorm.Create(run).Error; err != nil {
+		if a.logger != nil {
+			a.logger.Printf("record sync run failed: %v", err)
+		}
+		return nil
+	}
+	return run
+}
+
+func (a *AppService) finishSyncRun(run *models.SyncRun, summary SyncSummary, runErr error) {
+	if run == nil {
+		return
+	}
+
+	now := time.Now()
+	results := make([]models.SyncRunResult, 0, len(summary.Channels)+len(summary.Playlists))
+	for _, r := range summary.Channels {
+		results = append(results, models.SyncRunResult{
+			SyncRunID:     run.ID,
+			Kind:          "channel",
+			FeedID:        r.ChannelID,
+			Name:          r.ChannelName,
+			NewVideos:     r.NewVideos,
+			UpdatedVideos: r.UpdatedVideos,
+			RemovedVideos: r.RemovedVideos,
+			Unchanged:     r.Unchanged,
+			Error:         r.Error,
+		})
+	}
+	for _, r := range summary.Playlists {
+		results = append(results, models.SyncRunResult{
+			SyncRunID:     run.ID,
+			Kind:          "playlist",
+			FeedID:        r.PlaylistID,
+			Name:          r.Title,
+			NewVideos:     r.NewVideos,
+			UpdatedVideos: r.UpdatedVideos,
+			Error:         r.Error,
+		})
+	}
+
+	updates := map[string]interface{}{
+		"finished_at":     &now,
+		"total_new":       summary.TotalNew,
+		"total_updated":   summary.TotalUpdated,
+		"total_unchanged": summary.TotalUnchanged,
+		"total_failed":    summary.TotalFailed,
+	}
+	if runErr != nil {
+		updates["error"] = runErr.Error()
+	}
+
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+
+	if err := a.DB.Gorm.Model(&models.SyncRun{}).Where("id = ?", run.ID).Updates(updates).Error; err != nil && a.logger != nil {
+		a.logger.Printf("record sync run failed: %v", err)
+	}
+	if len(results) > 0 {
+		if err := a.DB.Gorm.CreateInBatches(results, 100).Error; err != nil && a.logger != nil {
+			a.logger.Printf("record sync run results failed: %v", err)
+		}
+	}
+	a.pruneSyncRuns(now.Add(-syncRunRetention))
+}
+
+func (a *AppService) pruneSyncRuns(before time.Time) {
+	old := a.DB.Gorm.Model(&models.SyncRun{}).Select("id").Where("started_at < ?", before)
+	if err := a.DB.Gorm.Where("sync_run_id IN (?)", old).Delete(&models.SyncRunResult{}).Error; err != nil {
+		return
+	}
+	_ = a.DB.Gorm.Where("started_at < ?", before).Delete(&models.SyncRun{}).Error
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+// cacheThumbnails downloads the thumbnails of synced entries into the local
+// cache. It runs after the sync write lock is released since every download
+// is a network round trip.
+func (a *AppService) cacheThumbnails(ctx context.Context, entries []services.FeedEntry) {
+	if a.Thumbnails == nil || len(entries) == 0 {
+		return
+	}
+	settings, err := a.GetAppSettings()
+	if err != nil || !settings.ThumbnailCacheEnabled {
+		return
+	}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := a.Thumbnails.Fetch(ctx, entry.VideoID, entry.Thumbnail); err != nil && a.logger != nil {
+			a.logger.Printf("cache thumbnail failed: %s: %v", entry.VideoID, err)
+		}
+	}
+}
+
+// localThumbnails points cached thumbnails at the asset route so the feed
+// renders offline. Videos that are not cached keep the remote URL, as do all
+// videos while the cache is turned off.
+func (a *AppService) localThumbnails(videos []VideoItem) {
+	if a.Thumbnails == nil || !getSettingBool(a.DB, "thumbnail_cache_enabled", true) {
+		return
+	}
+	for i := range videos {
+		if local := a.Thumbnails.LocalURL(videos[i].VideoID); local != "" {
+			videos[i].Thumbnail = local
+		}
+	}
+}
+
+// removeThumbnails drops the cached files of deleted videos.
+func (a *AppService) removeThumbnails(videos []models.Video) {
+	if a.Thumbnails == nil {
+		return
+	}
+	for _, video := range videos {
+		a.Thumbnails.Remove(video.VideoID)
+	}
+}
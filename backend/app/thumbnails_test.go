@@ -0,0 +1,26 @@
+package app
+
+import (
+	"testing"
+
+	"ytfeedgenerator/backend/services"
+)
+
+func TestLocalThumbnailsFollowsCacheSetting(t *testing.T) {
+	a := newTestAppService(t)
+	writeCachedThumbnail(t, a, "dQw4w9WgXcQ")
+	const remote = "https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg"
+
+	videos := []VideoItem{{VideoID: "dQw4w9WgXcQ", Thumbnail: remote}}
+	a.localThumbnails(videos)
+	if videos[0].Thumbnail != services.ThumbnailRoutePrefix+"dQw4w9WgXcQ.jpg" {
+		t.Fatalf("enabled cache: thumbnail = %q", videos[0].Thumbnail)
+	}
+
+	setSetting(a.DB, "thumbnail_cache_enabled", "false")
+	videos = []VideoItem{{VideoID: "dQw4w9WgXcQ", Thumbnail: remote}}
+	a.localThumbnails(videos)
+	if videos[0].Thumbnail != remote {
+		t.Fatalf("disabled cache: thumbnail = %q, want the remote URL", videos[0].Thumbnail)
+	}
+}
@@ -0,0 +1,232 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+
+	"gorm.io/gorm"
+)
+
+// defaultTranscriptLanguages is used until the languages setting is saved.
+const defaultTranscriptLanguages = "ko,en"
+
+type TranscriptSegmentItem struct {
+	Start    float64
+	Duration float64
+	Text     string
+	URL      string
+}
+
+// ListTranscriptSegments returns the timed transcript of a video. Each
+// segment links to its offset in the video.
+func (a *AppService) ListTranscriptSegments(videoID string) ([]TranscriptSegmentItem, error) {
+	if strings.TrimSpace(videoID) == "" {
+		return nil, fmt.Errorf("videoID is required")
+	}
+	var video models.Video
+	if err := a.DB.Gorm.Where("video_id = ?", videoID).First(&video).Error; err != nil {
+		return nil, err
+	}
+	var segments []models.TranscriptSegment
+	if err := a.DB.Gorm.Where("video_id = ?", video.ID).Order("position asc").Find(&segments).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]TranscriptSegmentItem, 0, len(segments))
+	for _, s := range segments {
+		items = append(items, TranscriptSegmentItem{
+			Start:    s.StartSeconds,
+			Duration: s.DurationSeconds,
+			Text:     s.Text,
+			URL:      services.TimestampURL(video.URL, s.StartSeconds),
+		})
+	}
+	return items, nil
+}
+
+// SetChannelTranscriptPreferences sets the ordered caption languages and the
+// track choice for a channel's videos. Empty values fall back to the app
+// settings.
+func (a *AppService) SetChannelTranscriptPreferences(channelID string, languages []string, track string) error {
+	if strings.TrimSpace(channelID) == "" {
+		return fmt.Errorf("channelID is required")
+	}
+	track = strings.TrimSpace(track)
+	if track != "" && !services.IsValidTranscriptTrack(track) {
+		return fmt.Errorf("unknown transcript track: %s", track)
+	}
+	return a.DB.Gorm.Model(&models.Channel{}).
+		Where("channel_id = ?", channelID).
+		Updates(map[string]interface{}{
+			"transcript_languages": strings.Join(normalizeLanguages(languages), ","),
+			"transcript_track":     track,
+		}).Error
+}
+
+// SetVideoTranscriptPreferences overrides the channel's caption preferences
+// for one video. A stored transcript fetched under other preferences is
+// dropped, and its retry schedule cleared, so the next summary picks up the
+// preferred track right away.
+func (a *AppService) SetVideoTranscriptPreferences(videoID string, languages []string, track string) error {
+	if strings.TrimSpace(videoID) == "" {
+		return fmt.Errorf("videoID is required")
+	}
+	track = strings.TrimSpace(track)
+	if track != "" && !services.IsValidTranscriptTrack(track) {
+		return fmt.Errorf("unknown transcript track: %s", track)
+	}
+	var video models.Video
+	if err := a.DB.Gorm.Where("video_id = ?", videoID).First(&video).Error; err != nil {
+		return err
+	}
+
+	joined := strings.Join(normalizeLanguages(languages), ",")
+	if joined == video.TranscriptLanguages && track == video.TranscriptTrack {
+		return nil
+	}
+	return a.saveTranscript(video.ID, services.Transcript{}, map[string]interface{}{
+		"transcript_languages":    joined,
+		"transcript_track":        track,
+		"transcript_status":       "",
+		"transcript_last_error":   "",
+		"transcript_attempts":     0,
+		"transcript_next_attempt": nil,
+	})
+}
+
+// transcriptPreferences resolves the caption languages and track for a
+// video: the video's own override, then its channel's, then the settings.
+func (a *AppService) transcriptPreferences(video models.Video) ([]string, string) {
+	languageList := video.TranscriptLanguages
+	track := video.TranscriptTrack
+	if (languageList == "" || track == "") && video.ChannelID != 0 {
+		var channel models.Channel
+		if err := a.DB.Gorm.Select("transcript_languages", "transcript_track").First(&channel, video.ChannelID).Error; err == nil {
+			if languageList == "" {
+				languageList = channel.TranscriptLanguages
+			}
+			if track == "" {
+				track = channel.TranscriptTrack
+			}
+		}
+	}
+	if languageList == "" || track == "" {
+		settings, _ := a.GetAppSettings()
+		if languageList == "" {
+			languageList = settings.TranscriptLanguages
+		}
+		if track == "" {
+			track = settings.TranscriptTrack
+		}
+	}
+
+	languages := normalizeLanguages(strings.Split(languageList, ","))
+	if len(languages) == 0 {
+		languages = strings.Split(defaultTranscriptLanguages, ",")
+	}
+	if !services.IsValidTranscriptTrack(track) {
+		track = services.TranscriptTrackPreferManual
+	}
+	return languages, track
+}
+
+// fetchTranscript fetches the preferred caption track and, when every
+// provider reports that the video has no captions, falls back to local
+// speech-to-text if it is enabled. Other failures, rate limits above all, do
+// not trigger the fallback since the captions may well exist; rate limits
+// feed the circuit breaker instead, which fails fetches fast while open.
+func (a *AppService) fetchTranscript(ctx context.Context, video models.Video) (services.Transcript, error) {
+	if wait := a.transcriptBreaker.wait(time.Now()); wait > 0 {
+		return services.Transcript{}, fmt.Errorf("%w (%d minutes remaining)", errTranscriptPaused, waitMinutes(wait))
+	}
+	languages, track := a.transcriptPreferences(video)
+	transcript, err := a.Transcript.FetchTranscript(ctx, video.VideoID, languages, track)
+	a.transcriptBreaker.record(isRateLimitError(err), time.Now())
+	if err == nil || !services.IsNoTranscript(err) {
+		return transcript, err
+	}
+
+	settings, settingsErr := a.GetAppSettings()
+	if settingsErr != nil || !settings.ASREnabled {
+		return transcript, err
+	}
+	duration := video.DurationSeconds
+	if duration <= 0 && settings.YTDLPEnabled {
+		if enriched, enrichErr := a.enrichStoredVideo(ctx, video); enrichErr == nil {
+			duration = enriched.DurationSeconds
+		}
+	}
+	asr, asrErr := a.Transcript.Transcribe(ctx, services.ASRConfig{
+		ExtractCommand:     settings.ASRExtractCommand,
+		YTDLPPath:          settings.YTDLPPath,
+		WhisperPath:        settings.ASRWhisperPath,
+		ModelPath:          settings.ASRModelPath,
+		Language:           settings.ASRLanguage,
+		MaxDurationSeconds: settings.ASRMaxDurationMinutes * 60,
+		Concurrency:        settings.ASRConcurrency,
+	}, video.URL, duration)
+	if asrErr != nil {
+		return services.Transcript{}, fmt.Errorf("%v; speech-to-text: %w", err, asrErr)
+	}
+	return asr, nil
+}
+
+// normalizeLanguages trims and de-duplicates language codes, keeping their
+// order. Case is kept since caption tracks are matched by exact code.
+func normalizeLanguages(languages []string) []string {
+	out := make([]string, 0, len(languages))
+	seen := make(map[string]bool, len(languages))
+	for _, lang := range languages {
+		lang = strings.TrimSpace(lang)
+		if lang == "" || seen[lang] {
+			continue
+		}
+		seen[lang] = true
+		out = append(out, lang)
+	}
+	return out
+}
+
+// saveTranscript stores the transcript text, the language and track it came
+// from, and replaces the video's segments. extra carries the status columns
+// the caller updates alongside.
+func (a *AppService) saveTranscript(videoID uint, transcript services.Transcript, extra map[string]interface{}) error {
+	updates := map[string]interface{}{
+		"transcript":               transcript.Text,
+		"transcript_language_used": transcript.Language,
+		"transcript_track_used":    transcript.Track,
+		"transcript_source":        transcript.Source,
+	}
+	for k, v := range extra {
+		updates[k] = v
+	}
+
+	segments := make([]models.TranscriptSegment, 0, len(transcript.Segments))
+	for i, s := range transcript.Segments {
+		segments = append(segments, models.TranscriptSegment{
+			VideoID:         videoID,
+			Position:        i,
+			StartSeconds:    s.Start,
+			DurationSeconds: s.Duration,
+			Text:            s.Text,
+		})
+	}
+
+	return a.DB.Gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Video{}).Where("id = ?", videoID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("video_id = ?", videoID).Delete(&models.TranscriptSegment{}).Error; err != nil {
+			return err
+		}
+		if len(segments) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&segments, 200).Error
+	})
+}
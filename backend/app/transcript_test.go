@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+type noCaptionsProvider struct{}
+
+func (noCaptionsProvider) Name() string { return "no captions" }
+
+func (noCaptionsProvider) FetchTranscript(ctx context.Context, videoID string, languages []string, track string) (services.Transcript, error) {
+	return services.Transcript{}, services.ErrNoTranscript
+}
+
+func TestFetchTranscriptFallsBackToASROnlyWithoutCaptions(t *testing.T) {
+	a := newTestAppService(t)
+	setSetting(a.DB, "asr_enabled", "true")
+	setSetting(a.DB, "asr_whisper_path", "/nonexistent/whisper")
+	setSetting(a.DB, "asr_model_path", "/nonexistent/model.bin")
+	setSetting(a.DB, "ytdlp_path", "/nonexistent/yt-dlp")
+	video := models.Video{
+		VideoID:         "dQw4w9WgXcQ",
+		URL:             "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		DurationSeconds: 60,
+	}
+
+	tests := []struct {
+		name     string
+		provider services.TranscriptProvider
+		wantASR  bool
+	}{
+		{"no captions", noCaptionsProvider{}, true},
+		{"network error", &failingTranscriptProvider{err: errors.New("dial tcp: i/o timeout")}, false},
+		{"rate limited", &failingTranscriptProvider{err: fmt.Errorf("caption request failed: %w", services.ErrRateLimited)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a.transcriptBreaker.reset()
+			a.Transcript.Providers = []services.TranscriptProvider{tt.provider}
+			_, err := a.fetchTranscript(context.Background(), video)
+			if err == nil {
+				t.Fatal("fetchTranscript succeeded")
+			}
+			// The stand-in binaries do not exist, so an ASR run fails with
+			// its own message.
+			if gotASR := strings.Contains(err.Error(), "speech-to-text"); gotASR != tt.wantASR {
+				t.Fatalf("speech-to-text attempted = %v, want %v: %v", gotASR, tt.wantASR, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+// Transcript retries back off exponentially per video until
+// maxTranscriptAttempts failures, after which the video is marked gave_up
+// and summarized from its description.
+const (
+	maxTranscriptAttempts     = 6
+	transcriptRetryBase       = 30 * time.Minute
+	transcriptRateLimitBase   = 2 * time.Hour
+	transcriptRetryMax        = 48 * time.Hour
+	transcriptStatusGaveUp    = "gave_up"
+	transcriptBreakerTrips    = 3
+	transcriptBreakerCooldown = time.Hour
+	transcriptBreakerMax      = 12 * time.Hour
+)
+
+var (
+	errTranscriptCooldown = errors.New("transcript retry cooldown active")
+	errTranscriptPaused   = errors.New("transcript fetches paused after repeated rate limits")
+)
+
+type TranscriptFetchStatus struct {
+	Paused          bool
+	PausedUntil     *time.Time
+	RateLimitStreak int
+}
+
+// transcriptBreaker pauses every transcript fetch once transcriptBreakerTrips
+// rate limits arrive in a row. A trip that follows another without a fetch
+// getting through in between doubles the pause.
+type transcriptBreaker struct {
+	mu          sync.Mutex
+	streak      int
+	pausedUntil time.Time
+	cooldown    time.Duration
+}
+
+func (b *transcriptBreaker) wait(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+	return 0
+}
+
+func (b *transcriptBreaker) record(rateLimited bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !rateLimited {
+		b.streak = 0
+		b.cooldown = 0
+		return
+	}
+	b.streak++
+	if b.streak < transcriptBreakerTrips {
+		return
+	}
+	if b.cooldown == 0 {
+		b.cooldown = transcriptBreakerCooldown
+	} else if b.cooldown < transcriptBreakerMax {
+		b.cooldown *= 2
+		if b.cooldown > transcriptBreakerMax {
+			b.cooldown = transcriptBreakerMax
+		}
+	}
+	b.pausedUntil = now.Add(b.cooldown)
+	b.streak = 0
+}
+
+func (b *transcriptBreaker) status(now time.Time) TranscriptFetchStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := TranscriptFetchStatus{RateLimitStreak: b.streak}
+	if now.Before(b.pausedUntil) {
+		until := b.pausedUntil
+		status.Paused = true
+		status.PausedUntil = &until
+	}
+	return status
+}
+
+func (b *transcriptBreaker) reset() {
+	b.mu.Lock()
+	b.streak = 0
+	b.cooldown = 0
+	b.pausedUntil = time.Time{}
+	b.mu.Unlock()
+}
+
+func (a *AppService) GetTranscriptFetchStatus() TranscriptFetchStatus {
+	return a.transcriptBreaker.status(time.Now())
+}
+
+// ResumeTranscriptFetches closes the circuit breaker before its pause ends.
+func (a *AppService) ResumeTranscriptFetches() {
+	a.transcriptBreaker.reset()
+}
+
+// RetryTranscript clears a video's retry schedule, including the gave_up
+// state, so the next summary fetches the transcript right away.
+func (a *AppService) RetryTranscript(videoID string) error {
+	if strings.TrimSpace(videoID) == "" {
+		return fmt.Errorf("videoID is required")
+	}
+	return a.DB.Gorm.Model(&models.Video{}).
+		Where("video_id = ?", videoID).
+		Updates(map[string]interface{}{
+			"transcript_status":       "",
+			"transcript_attempts":     0,
+			"transcript_next_attempt": nil,
+		}).Error
+}
+
+// fetchScheduledTranscript fetches and stores a video's transcript under its
+// retry schedule. A failure is recorded and returns errTranscriptCooldown
+// until the video runs out of retries; the final failure marks it gave_up
+// and returns the fetch error itself.
+func (a *AppService) fetchScheduledTranscript(ctx context.Context, video models.Video) (services.Transcript, error) {
+	now := time.Now()
+	if wait := transcriptRetryWait(video, now); wait > 0 {
+		return services.Transcript{}, fmt.Errorf("%w (%d minutes remaining)", errTranscriptCooldown, waitMinutes(wait))
+	}
+	_ = a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Updates(map[string]interface{}{
+		"transcript_last_attempt": &now,
+	}).Error
+
+	transcript, err := a.fetchTranscript(ctx, video)
+	if errors.Is(err, errTranscriptPaused) {
+		return services.Transcript{}, err
+	}
+	if err == nil && strings.TrimSpace(transcript.Text) == "" {
+		err = fmt.Errorf("transcript is empty")
+	}
+	if err != nil {
+		updates := transcriptFailureUpdates(video, err, now)
+		_ = a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Updates(updates).Error
+		if next, ok := updates["transcript_next_attempt"].(*time.Time); ok && next != nil {
+			return services.Transcript{}, fmt.Errorf("%w (%d minutes remaining): %v", errTranscriptCooldown, waitMinutes(next.Sub(now)), err)
+		}
+		return services.Transcript{}, err
+	}
+
+	_ = a.saveTranscript(video.ID, transcript, map[string]interface{}{
+		"transcript_status":       "ok",
+		"transcript_last_error":   "",
+		"transcript_last_attempt": &now,
+		"transcript_attempts":     0,
+		"transcript_next_attempt": nil,
+	})
+	return transcript, nil
+}
+
+// transcriptRetryWait reports how long a video must wait before its next
+// transcript fetch.
+func transcriptRetryWait(video models.Video, now time.Time) time.Duration {
+	if video.TranscriptNextAttempt == nil || !now.Before(*video.TranscriptNextAttempt) {
+		return 0
+	}
+	return video.TranscriptNextAttempt.Sub(now)
+}
+
+// transcriptFailureUpdates records a failed fetch and schedules the next one.
+func transcriptFailureUpdates(video models.Video, err error, now time.Time) map[string]interface{} {
+	attempts := video.TranscriptAttempts + 1
+	updates := map[string]interface{}{
+		"transcript_last_error":   err.Error(),
+		"transcript_last_attempt": &now,
+		"transcript_attempts":     attempts,
+	}
+	if attempts >= maxTranscriptAttempts {
+		updates["transcript_status"] = transcriptStatusGaveUp
+		updates["transcript_next_attempt"] = nil
+		return updates
+	}
+
+	status := "failed"
+	base := transcriptRetryBase
+	if isRateLimitError(err) {
+		status = "rate_limited"
+		base = transcriptRateLimitBase
+	}
+	next := now.Add(transcriptRetryDelay(base, attempts))
+	updates["transcript_status"] = status
+	updates["transcript_next_attempt"] = &next
+	return updates
+}
+
+// transcriptRetryDelay doubles base for every earlier attempt up to
+// transcriptRetryMax and spreads it by up to ±20% so videos that failed
+// together do not retry together.
+func transcriptRetryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < transcriptRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > transcriptRetryMax {
+		delay = transcriptRetryMax
+	}
+	jitter := time.Duration((rand.Float64()*0.4 - 0.2) * float64(delay))
+	return delay + jitter
+}
+
+func isRateLimitError(err error) bool {
+	return errors.Is(err, services.ErrRateLimited)
+}
+
+func waitMinutes(wait time.Duration) int {
+	minutes := int(wait.Round(time.Minute).Minutes())
+	if minutes < 1 {
+		minutes = 1
+	}
+	return minutes
+}
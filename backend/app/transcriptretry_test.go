@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+// failingTranscriptProvider fails every fetch with err and counts the calls.
+type failingTranscriptProvider struct {
+	err   error
+	calls int
+}
+
+func (p *failingTranscriptProvider) Name() string { return "failing" }
+
+func (p *failingTranscriptProvider) FetchTranscript(ctx context.Context, videoID string, languages []string, track string) (services.Transcript, error) {
+	p.calls++
+	return services.Transcript{}, p.err
+}
+
+// newOllamaStandIn answers every chat request with "summary" and hands the
+// user prompts it received to prompts.
+func newOllamaStandIn(t *testing.T, prompts *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []services.ChatMessage `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, m := range req.Messages {
+			if m.Role == "user" {
+				*prompts = append(*prompts, m.Content)
+			}
+		}
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"summary"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func createTestVideo(t *testing.T, a *AppService, video models.Video) models.Video {
+	t.Helper()
+	channel := models.Channel{ChannelID: testChannelID, Name: "Test", SourceType: services.SourceYouTube}
+	if err := a.DB.Gorm.Where(models.Channel{ChannelID: testChannelID}).FirstOrCreate(&channel).Error; err != nil {
+		t.Fatalf("create channel: %v", err)
+	}
+	video.ChannelID = channel.ID
+	if err := a.DB.Gorm.Create(&video).Error; err != nil {
+		t.Fatalf("create video: %v", err)
+	}
+	return video
+}
+
+func TestSummarizeVideoKeepsFailedTranscriptsQueued(t *testing.T) {
+	a := newTestAppService(t)
+	provider := &failingTranscriptProvider{err: fmt.Errorf("caption request failed: %w", services.ErrRateLimited)}
+	a.Transcript.Providers = []services.TranscriptProvider{provider}
+	var prompts []string
+	llm := newOllamaStandIn(t, &prompts)
+
+	video := createTestVideo(t, a, models.Video{
+		VideoID:     "dQw4w9WgXcQ",
+		Title:       "Video",
+		URL:         "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		Description: "the description",
+	})
+
+	_, err := a.SummarizeVideo(video.VideoID, "", "ollama", "llama3", llm.URL, "", 0)
+	if !errors.Is(err, errTranscriptCooldown) {
+		t.Fatalf("first failure: err = %v, want the cooldown error", err)
+	}
+	var stored models.Video
+	a.DB.Gorm.First(&stored, video.ID)
+	if stored.Summary != "" || len(prompts) != 0 {
+		t.Fatalf("video was summarized after one transient failure")
+	}
+	if stored.TranscriptAttempts != 1 || stored.TranscriptNextAttempt == nil || stored.TranscriptStatus != "rate_limited" {
+		t.Fatalf("retry schedule not recorded: attempts=%d next=%v status=%q", stored.TranscriptAttempts, stored.TranscriptNextAttempt, stored.TranscriptStatus)
+	}
+
+	// A second call inside the cooldown does not fetch again.
+	if _, err := a.SummarizeVideo(video.VideoID, "", "ollama", "llama3", llm.URL, "", 0); !errors.Is(err, errTranscriptCooldown) {
+		t.Fatalf("cooldown: err = %v", err)
+	}
+	if provider.calls != 1 {
+		t.Fatalf("provider called %d times during cooldown", provider.calls)
+	}
+
+	// The last retry gives up and summarizes the description.
+	past := time.Now().Add(-time.Minute)
+	a.DB.Gorm.Model(&models.Video{}).Where("id = ?", video.ID).Updates(map[string]interface{}{
+		"transcript_attempts":     maxTranscriptAttempts - 1,
+		"transcript_next_attempt": &past,
+	})
+	summary, err := a.SummarizeVideo(video.VideoID, "", "ollama", "llama3", llm.URL, "", 0)
+	if err != nil {
+		t.Fatalf("after the last retry: %v", err)
+	}
+	if summary != "summary" || len(prompts) != 1 || !strings.Contains(prompts[0], "the description") {
+		t.Fatalf("description not summarized: summary=%q prompts=%q", summary, prompts)
+	}
+	a.DB.Gorm.First(&stored, video.ID)
+	if stored.TranscriptStatus != transcriptStatusGaveUp {
+		t.Fatalf("status = %q, want %q", stored.TranscriptStatus, transcriptStatusGaveUp)
+	}
+}
+
+func TestAutoTagVideoFollowsRetrySchedule(t *testing.T) {
+	a := newTestAppService(t)
+	provider := &failingTranscriptProvider{err: fmt.Errorf("caption request failed: %w", services.ErrRateLimited)}
+	a.Transcript.Providers = []services.TranscriptProvider{provider}
+	var prompts []string
+	llm := newOllamaStandIn(t, &prompts)
+
+	video := createTestVideo(t, a, models.Video{
+		VideoID:     "dQw4w9WgXcQ",
+		URL:         "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		Description: "the description",
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := a.AutoTagVideo(video.VideoID, "ollama", "llama3", llm.URL, "", 0); err != nil {
+			t.Fatalf("AutoTagVideo: %v", err)
+		}
+	}
+	if provider.calls != 1 {
+		t.Fatalf("provider called %d times, want 1 with the second call in cooldown", provider.calls)
+	}
+	var stored models.Video
+	a.DB.Gorm.First(&stored, video.ID)
+	if stored.TranscriptAttempts != 1 || stored.TranscriptNextAttempt == nil {
+		t.Fatalf("failure not recorded: attempts=%d next=%v", stored.TranscriptAttempts, stored.TranscriptNextAttempt)
+	}
+}
+
+func TestSetVideoTranscriptPreferencesClearsRetrySchedule(t *testing.T) {
+	a := newTestAppService(t)
+	next := time.Now().Add(time.Hour)
+	video := createTestVideo(t, a, models.Video{
+		VideoID:               "dQw4w9WgXcQ",
+		TranscriptStatus:      "failed",
+		TranscriptAttempts:    maxTranscriptAttempts - 1,
+		TranscriptNextAttempt: &next,
+	})
+
+	if err := a.SetVideoTranscriptPreferences(video.VideoID, []string{"en"}, services.TranscriptTrackManual); err != nil {
+		t.Fatalf("SetVideoTranscriptPreferences: %v", err)
+	}
+	var stored models.Video
+	a.DB.Gorm.First(&stored, video.ID)
+	if stored.TranscriptStatus != "" || stored.TranscriptAttempts != 0 || stored.TranscriptNextAttempt != nil {
+		t.Fatalf("retry schedule kept: status=%q attempts=%d next=%v", stored.TranscriptStatus, stored.TranscriptAttempts, stored.TranscriptNextAttempt)
+	}
+}
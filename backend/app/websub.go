@@ -0,0 +1,469 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+const (
+	defaultWebSubListenAddr = "127.0.0.1:8765"
+	webSubLeaseSeconds      = 5 * 24 * 60 * 60
+	webSubRenewBefore       = 24 * time.Hour
+	webSubRetryAfter        = time.Hour
+)
+
+const (
+	WebSubStatePending       = "pending"
+	WebSubStateVerified      = "verified"
+	WebSubStateDenied        = "denied"
+	WebSubStateUnsubscribing = "unsubscribing"
+	WebSubStateFailed        = "failed"
+)
+
+type WebSubStatus struct {
+	Enabled       bool
+	Running       bool
+	ListenAddr    string
+	CallbackURL   string
+	HubURL        string
+	Subscriptions int64
+	Verified      int64
+}
+
+// StartWebSub (re)starts the callback server from the current settings. It
+// stops the server when WebSub is disabled.
+func (a *AppService) StartWebSub() error {
+	a.StopWebSub()
+
+	settings, err := a.GetAppSettings()
+	if err != nil {
+		return err
+	}
+	if !settings.WebSubEnabled {
+		return nil
+	}
+	if strings.TrimSpace(settings.WebSubCallbackURL) == "" {
+		return fmt.Errorf("websub callback url is required")
+	}
+	addr := strings.TrimSpace(settings.WebSubListenAddr)
+	if addr == "" {
+		addr = defaultWebSubListenAddr
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:           http.HandlerFunc(a.serveWebSub),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	a.webSubMu.Lock()
+	a.webSubServer = server
+	a.webSubAddr = listener.Addr().String()
+	a.webSubMu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed && a.logger != nil {
+			a.logger.Printf("websub server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (a *AppService) StopWebSub() {
+	a.webSubMu.Lock()
+	server := a.webSubServer
+	a.webSubServer = nil
+	a.webSubAddr = ""
+	a.webSubMu.Unlock()
+
+	if server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
+}
+
+func (a *AppService) GetWebSubStatus() (WebSubStatus, error) {
+	settings, err := a.GetAppSettings()
+	if err != nil {
+		return WebSubStatus{}, err
+	}
+
+	a.webSubMu.Lock()
+	running := a.webSubServer != nil
+	addr := a.webSubAddr
+	a.webSubMu.Unlock()
+	if addr == "" {
+		addr = settings.WebSubListenAddr
+	}
+
+	status := WebSubStatus{
+		Enabled:     settings.WebSubEnabled,
+		Running:     running,
+		ListenAddr:  addr,
+		CallbackURL: settings.WebSubCallbackURL,
+		HubURL:      settings.WebSubHubURL,
+	}
+	if err := a.DB.Gorm.Model(&models.WebSubSubscription{}).Count(&status.Subscriptions).Error; err != nil {
+		return WebSubStatus{}, err
+	}
+	if err := a.DB.Gorm.Model(&models.WebSubSubscription{}).Where("state = ?", WebSubStateVerified).Count(&status.Verified).Error; err != nil {
+		return WebSubStatus{}, err
+	}
+	return status, nil
+}
+
+func (a *AppService) ListWebSubSubscriptions() ([]models.WebSubSubscription, error) {
+	var subs []models.WebSubSubscription
+	if err := a.DB.Gorm.Order("channel_id asc").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// RenewWebSubLeases subscribes YouTube channels that have no lease or whose
+// lease ends within a day, and unsubscribes topics for deleted or paused
+// channels. It returns the number of hub requests sent.
+func (a *AppService) RenewWebSubLeases() (int, error) {
+	settings, err := a.GetAppSettings()
+	if err != nil {
+		return 0, err
+	}
+	if !settings.WebSubEnabled {
+		return 0, nil
+	}
+	if strings.TrimSpace(settings.WebSubCallbackURL) == "" {
+		return 0, fmt.Errorf("websub callback url is required")
+	}
+	// Without the callback server every hub verification fails, which
+	// would only mark each subscription failed.
+	a.webSubMu.Lock()
+	running := a.webSubServer != nil
+	a.webSubMu.Unlock()
+	if !running {
+		return 0, fmt.Errorf("websub server is not running")
+	}
+	hubURL := strings.TrimSpace(settings.WebSubHubURL)
+	if hubURL == "" {
+		hubURL = services.DefaultWebSubHubURL
+	}
+	secret, err := a.webSubSecret()
+	if err != nil {
+		return 0, err
+	}
+
+	var channels []models.Channel
+	if err := a.DB.Gorm.Where("paused = ? AND (source_type = ? OR source_type = '' OR source_type IS NULL)", false, services.SourceYouTube).Find(&channels).Error; err != nil {
+		return 0, err
+	}
+	var subs []models.WebSubSubscription
+	if err := a.DB.Gorm.Find(&subs).Error; err != nil {
+		return 0, err
+	}
+	byChannel := make(map[string]models.WebSubSubscription, len(subs))
+	for _, sub := range subs {
+		byChannel[sub.ChannelID] = sub
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	sent := 0
+	active := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		active[channel.ChannelID] = true
+		sub, ok := byChannel[channel.ChannelID]
+		if ok && !webSubRenewalDue(sub, hubURL, now) {
+			continue
+		}
+
+		sub.ChannelID = channel.ChannelID
+		sub.Topic = services.ChannelTopicURL(channel.ChannelID)
+		sub.HubURL = hubURL
+		sub.State = WebSubStatePending
+		sub.LastError = ""
+		sub.UpdatedAt = now
+		// Stored before the request because the hub may verify the
+		// callback before it answers. The lock is released for the request
+		// since that verification writes too.
+		a.syncWriteMu.Lock()
+		err := a.DB.Gorm.Save(&sub).Error
+		a.syncWriteMu.Unlock()
+		if err != nil {
+			return sent, err
+		}
+
+		err = a.WebSub.Subscribe(ctx, services.WebSubRequest{
+			HubURL:       hubURL,
+			Topic:        sub.Topic,
+			Callback:     settings.WebSubCallbackURL,
+			Secret:       secret,
+			LeaseSeconds: webSubLeaseSeconds,
+		})
+		sent++
+		if err != nil {
+			a.syncWriteMu.Lock()
+			_ = a.DB.Gorm.Model(&models.WebSubSubscription{}).Where("id = ?", sub.ID).Updates(map[string]interface{}{
+				"state":      WebSubStateFailed,
+				"last_error": err.Error(),
+			}).Error
+			a.syncWriteMu.Unlock()
+			if a.logger != nil {
+				a.logger.Printf("websub subscribe failed: %s: %v", channel.ChannelID, err)
+			}
+		}
+	}
+
+	for _, sub := range subs {
+		if active[sub.ChannelID] {
+			continue
+		}
+		a.syncWriteMu.Lock()
+		err := a.DB.Gorm.Model(&models.WebSubSubscription{}).Where("id = ?", sub.ID).Update("state", WebSubStateUnsubscribing).Error
+		a.syncWriteMu.Unlock()
+		if err != nil {
+			return sent, err
+		}
+		err = a.WebSub.Unsubscribe(ctx, services.WebSubRequest{
+			HubURL:   sub.HubURL,
+			Topic:    sub.Topic,
+			Callback: settings.WebSubCallbackURL,
+		})
+		sent++
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Printf("websub unsubscribe failed: %s: %v", sub.ChannelID, err)
+			}
+			// The lease simply runs out if the hub cannot be reached.
+			a.syncWriteMu.Lock()
+			_ = a.DB.Gorm.Delete(&models.WebSubSubscription{}, sub.ID).Error
+			a.syncWriteMu.Unlock()
+		}
+	}
+	return sent, nil
+}
+
+func webSubRenewalDue(sub models.WebSubSubscription, hubURL string, now time.Time) bool {
+	if sub.HubURL != hubURL {
+		return true
+	}
+	switch sub.State {
+	case WebSubStateVerified:
+		return sub.ExpiresAt == nil || sub.ExpiresAt.Before(now.Add(webSubRenewBefore))
+	case WebSubStateUnsubscribing:
+		return true
+	default:
+		// Pending, denied and failed requests are retried on a slower
+		// cadence so an unreachable hub is not hammered.
+		return sub.UpdatedAt.Before(now.Add(-webSubRetryAfter))
+	}
+}
+
+// webSubSecret returns the HMAC secret shared with the hub, creating it on
+// first use.
+func (a *AppService) webSubSecret() (string, error) {
+	if enc := getSetting(a.DB, "websub_secret", ""); enc != "" {
+		if dec, err := decryptString(enc); err == nil && dec != "" {
+			return dec, nil
+		}
+	}
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(buf)
+	enc, err := encryptString(secret)
+	if err != nil {
+		return "", err
+	}
+	setSetting(a.DB, "websub_secret", enc)
+	return secret, nil
+}
+
+func (a *AppService) serveWebSub(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.verifyWebSub(w, r)
+	case http.MethodPost:
+		a.receiveWebSub(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifyWebSub answers the hub's intent verification for topics we asked
+// for and records the granted lease.
+func (a *AppService) verifyWebSub(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	mode := query.Get("hub.mode")
+	topic := query.Get("hub.topic")
+
+	var sub models.WebSubSubscription
+	if err := a.DB.Gorm.Where("topic = ?", topic).First(&sub).Error; err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	now := time.Now()
+	switch mode {
+	case "subscribe":
+		if sub.State == WebSubStateUnsubscribing {
+			http.NotFound(w, r)
+			return
+		}
+		lease, _ := strconv.Atoi(query.Get("hub.lease_seconds"))
+		updates := map[string]interface{}{
+			"state":         WebSubStateVerified,
+			"lease_seconds": lease,
+			"last_error":    "",
+		}
+		if lease > 0 {
+			expires := now.Add(time.Duration(lease) * time.Second)
+			updates["expires_at"] = &expires
+		}
+		a.syncWriteMu.Lock()
+		err := a.DB.Gorm.Model(&models.WebSubSubscription{}).Where("id = ?", sub.ID).Updates(updates).Error
+		a.syncWriteMu.Unlock()
+		if err != nil {
+			http.Error(w, "store lease failed", http.StatusInternalServerError)
+			return
+		}
+	case "unsubscribe":
+		if sub.State != WebSubStateUnsubscribing {
+			http.NotFound(w, r)
+			return
+		}
+		a.syncWriteMu.Lock()
+		err := a.DB.Gorm.Delete(&models.WebSubSubscription{}, sub.ID).Error
+		a.syncWriteMu.Unlock()
+		if err != nil {
+			http.Error(w, "delete subscription failed", http.StatusInternalServerError)
+			return
+		}
+	case "denied":
+		a.syncWriteMu.Lock()
+		defer a.syncWriteMu.Unlock()
+		_ = a.DB.Gorm.Model(&models.WebSubSubscription{}).Where("id = ?", sub.ID).Updates(map[string]interface{}{
+			"state":      WebSubStateDenied,
+			"last_error": query.Get("hub.reason"),
+		}).Error
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		http.Error(w, "unknown hub.mode", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, query.Get("hub.challenge"))
+}
+
+// receiveWebSub stores a pushed notification through the same upsert path as
+// a poll. Bad signatures are acknowledged but ignored, as the spec requires.
+func (a *AppService) receiveWebSub(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "read body failed", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+
+	secret, err := a.webSubSecret()
+	if err != nil || !a.WebSub.VerifySignature(secret, body, r.Header.Get("X-Hub-Signature")) {
+		if a.logger != nil {
+			a.logger.Printf("websub push ignored: invalid signature")
+		}
+		return
+	}
+
+	notification, err := a.WebSub.ParseNotification(body)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Printf("websub push ignored: %v", err)
+		}
+		return
+	}
+	if _, err := a.storeWebSubNotification(context.Background(), notification); err != nil && a.logger != nil {
+		a.logger.Printf("websub push failed: %s: %v", notification.ChannelID, err)
+	}
+}
+
+func (a *AppService) storeWebSubNotification(ctx context.Context, notification *services.WebSubNotification) (SyncResult, error) {
+	var channel models.Channel
+	if notification.ChannelID != "" {
+		if err := a.DB.Gorm.Where("channel_id = ?", notification.ChannelID).First(&channel).Error; err != nil {
+			return SyncResult{}, fmt.Errorf("channel %s is not subscribed", notification.ChannelID)
+		}
+		if channel.Paused {
+			return SyncResult{ChannelID: channel.ChannelID, ChannelName: channel.Name, Unchanged: true}, nil
+		}
+	}
+
+	a.classifyNewEntries(ctx, notification.Entries)
+	rules := a.loadRules()
+	defer a.cacheThumbnails(ctx, notification.Entries)
+
+	result, err := a.applyWebSubNotification(channel, notification, rules)
+	if err != nil {
+		return result, err
+	}
+
+	if result.NewVideos > 0 && !channel.Muted && a.GetSyncSettings().NotificationsEnabled {
+		msg := fmt.Sprintf("%s: %d new", channel.Name, result.NewVideos)
+		_ = a.Notification.Notify(nil, "New videos detected", msg)
+	}
+	return result, nil
+}
+
+// applyWebSubNotification writes a push under syncWriteMu. Tombstones only
+// remove videos of the pushed channel, so a push that names no subscribed
+// channel deletes nothing.
+func (a *AppService) applyWebSubNotification(channel models.Channel, notification *services.WebSubNotification, rules []compiledRule) (SyncResult, error) {
+	a.syncWriteMu.Lock()
+	defer a.syncWriteMu.Unlock()
+
+	now := time.Now()
+	result := SyncResult{ChannelID: channel.ChannelID, ChannelName: channel.Name, Muted: channel.Muted}
+	if channel.ID == 0 {
+		return result, nil
+	}
+	if len(notification.Entries) > 0 {
+		newCount, updatedCount, err := a.storeFeedEntries(channel, notification.Entries, rules, now)
+		if err != nil {
+			return result, err
+		}
+		result.NewVideos = newCount
+		result.UpdatedVideos = updatedCount
+	}
+
+	if len(notification.DeletedVideoIDs) > 0 {
+		tx := a.DB.Gorm.Model(&models.Video{}).
+			Where("channel_id = ? AND video_id IN ? AND removed = ?", channel.ID, notification.DeletedVideoIDs, false).
+			Updates(map[string]interface{}{
+				"removed":    true,
+				"removed_at": &now,
+			})
+		if tx.Error != nil {
+			return result, tx.Error
+		}
+		result.RemovedVideos = int(tx.RowsAffected)
+	}
+
+	_ = a.DB.Gorm.Model(&models.WebSubSubscription{}).Where("channel_id = ?", channel.ChannelID).Update("last_push_at", &now).Error
+	return result, nil
+}
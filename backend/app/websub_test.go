@@ -0,0 +1,228 @@
+package app
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"ytfeedgenerator/backend/models"
+	"ytfeedgenerator/backend/services"
+)
+
+// webSubHubStandIn verifies every subscribe request against the callback the
+// way a real hub does and records what it saw.
+type webSubHubStandIn struct {
+	mu        sync.Mutex
+	requests  []url.Values
+	challenge string
+	echoed    []string
+}
+
+func (h *webSubHubStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.mu.Lock()
+	h.requests = append(h.requests, r.PostForm)
+	h.mu.Unlock()
+
+	verify := url.Values{
+		"hub.mode":          {r.PostForm.Get("hub.mode")},
+		"hub.topic":         {r.PostForm.Get("hub.topic")},
+		"hub.challenge":     {h.challenge},
+		"hub.lease_seconds": {r.PostForm.Get("hub.lease_seconds")},
+	}
+	resp, err := http.Get(r.PostForm.Get("hub.callback") + "?" + verify.Encode())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	h.mu.Lock()
+	h.echoed = append(h.echoed, string(body))
+	h.mu.Unlock()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func newWebSubTestApp(t *testing.T) (*AppService, string, *webSubHubStandIn) {
+	t.Helper()
+	a := newTestAppService(t)
+	// Classification of pushed videos hits the watch page; keep it local.
+	pages := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(pages.Close)
+	a.YouTube.BaseURL = pages.URL
+
+	hub := &webSubHubStandIn{challenge: "challenge-123"}
+	hubServer := httptest.NewServer(hub)
+	t.Cleanup(hubServer.Close)
+
+	setSetting(a.DB, "websub_enabled", "true")
+	setSetting(a.DB, "websub_hub_url", hubServer.URL)
+	setSetting(a.DB, "websub_listen_addr", "127.0.0.1:0")
+	// The callback URL is only known once the server listens.
+	setSetting(a.DB, "websub_callback_url", "http://127.0.0.1/")
+	if err := a.StartWebSub(); err != nil {
+		t.Fatalf("StartWebSub: %v", err)
+	}
+	t.Cleanup(a.StopWebSub)
+	status, err := a.GetWebSubStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	callback := "http://" + status.ListenAddr + "/"
+	setSetting(a.DB, "websub_callback_url", callback)
+	if err := a.DB.Gorm.Create(&models.Channel{ChannelID: testChannelID, Name: "Test", SourceType: services.SourceYouTube}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return a, callback, hub
+}
+
+func TestWebSubSubscribeAndRenew(t *testing.T) {
+	a, _, hub := newWebSubTestApp(t)
+
+	sent, err := a.RenewWebSubLeases()
+	if err != nil || sent != 1 {
+		t.Fatalf("RenewWebSubLeases = %d, %v; want 1 request", sent, err)
+	}
+	secret, _ := a.webSubSecret()
+	req := hub.requests[0]
+	if req.Get("hub.mode") != "subscribe" || req.Get("hub.topic") != services.ChannelTopicURL(testChannelID) || req.Get("hub.secret") != secret {
+		t.Fatalf("hub received %v", req)
+	}
+	if len(hub.echoed) != 1 || hub.echoed[0] != hub.challenge {
+		t.Fatalf("callback echoed %q, want the challenge", hub.echoed)
+	}
+
+	var sub models.WebSubSubscription
+	if err := a.DB.Gorm.Where("channel_id = ?", testChannelID).First(&sub).Error; err != nil {
+		t.Fatal(err)
+	}
+	if sub.State != WebSubStateVerified || sub.LeaseSeconds != webSubLeaseSeconds || sub.ExpiresAt == nil {
+		t.Fatalf("subscription not verified: %+v", sub)
+	}
+
+	// A fresh lease is left alone; one about to run out is renewed.
+	if sent, err := a.RenewWebSubLeases(); err != nil || sent != 0 {
+		t.Fatalf("renew with a fresh lease = %d, %v", sent, err)
+	}
+	soon := time.Now().Add(time.Hour)
+	a.DB.Gorm.Model(&models.WebSubSubscription{}).Where("id = ?", sub.ID).Update("expires_at", &soon)
+	if sent, err := a.RenewWebSubLeases(); err != nil || sent != 1 {
+		t.Fatalf("renew with an expiring lease = %d, %v", sent, err)
+	}
+
+	// Without the callback server nothing is sent to the hub.
+	a.StopWebSub()
+	a.DB.Gorm.Model(&models.WebSubSubscription{}).Where("id = ?", sub.ID).Update("expires_at", &soon)
+	if sent, err := a.RenewWebSubLeases(); err == nil || sent != 0 {
+		t.Fatalf("renew without the server = %d, %v", sent, err)
+	}
+	var kept models.WebSubSubscription
+	a.DB.Gorm.First(&kept, sub.ID)
+	if kept.State != WebSubStateVerified {
+		t.Fatalf("state = %q after a skipped renewal", kept.State)
+	}
+}
+
+func TestWebSubVerificationRejectsUnknownTopic(t *testing.T) {
+	_, callback, _ := newWebSubTestApp(t)
+	verify := url.Values{
+		"hub.mode":      {"subscribe"},
+		"hub.topic":     {services.ChannelTopicURL("UCzzzzzzzzzzzzzzzzzzzzzz")},
+		"hub.challenge": {"nope"},
+	}
+	resp, err := http.Get(callback + "?" + verify.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || string(body) == "nope" {
+		t.Fatalf("unknown topic answered %d %q", resp.StatusCode, body)
+	}
+}
+
+func pushWebSub(t *testing.T, callback string, secret string, body string) {
+	t.Helper()
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(body))
+	req, _ := http.NewRequest(http.MethodPost, callback, bytes.NewReader([]byte(body)))
+	req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func webSubEntry(videoID string) string {
+	return `<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>yt:video:` + videoID + `</id>
+    <yt:videoId>` + videoID + `</yt:videoId>
+    <yt:channelId>` + testChannelID + `</yt:channelId>
+    <title>Pushed</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=` + videoID + `"/>
+    <published>2026-10-01T00:00:00+00:00</published>
+    <updated>2026-10-01T00:00:00+00:00</updated>
+  </entry>
+</feed>`
+}
+
+func webSubTombstone(videoID string) string {
+	return `<feed xmlns:at="http://purl.org/atompub/tombstones/1.0" xmlns="http://www.w3.org/2005/Atom">
+  <at:deleted-entry ref="yt:video:` + videoID + `" when="2026-10-02T00:00:00+00:00">
+    <link href="https://www.youtube.com/watch?v=` + videoID + `"/>
+    <at:by><name>Test</name><uri>https://www.youtube.com/channel/` + testChannelID + `</uri></at:by>
+  </at:deleted-entry>
+</feed>`
+}
+
+func TestWebSubPush(t *testing.T) {
+	a, callback, _ := newWebSubTestApp(t)
+	secret, err := a.webSubSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pushWebSub(t, callback, secret, webSubEntry("pushedVid01"))
+	pushWebSub(t, callback, "forged", webSubEntry("forgedVid01"))
+
+	var count int64
+	a.DB.Gorm.Model(&models.Video{}).Where("video_id = ?", "pushedVid01").Count(&count)
+	if count != 1 {
+		t.Fatal("signed push not stored")
+	}
+	a.DB.Gorm.Model(&models.Video{}).Where("video_id = ?", "forgedVid01").Count(&count)
+	if count != 0 {
+		t.Fatal("push with a bad signature stored")
+	}
+
+	// A tombstone from this channel must not remove another channel's video.
+	other := models.Channel{ChannelID: "UCotherotherotherother00", Name: "Other", SourceType: services.SourceYouTube}
+	a.DB.Gorm.Create(&other)
+	a.DB.Gorm.Create(&models.Video{VideoID: "otherVid001", ChannelID: other.ID})
+
+	pushWebSub(t, callback, secret, webSubTombstone("otherVid001"))
+	pushWebSub(t, callback, secret, webSubTombstone("pushedVid01"))
+
+	var otherVideo, ownVideo models.Video
+	a.DB.Gorm.Where("video_id = ?", "otherVid001").First(&otherVideo)
+	if otherVideo.Removed {
+		t.Fatal("tombstone removed another channel's video")
+	}
+	a.DB.Gorm.Where("video_id = ?", "pushedVid01").First(&ownVideo)
+	if !ownVideo.Removed {
+		t.Fatal("tombstone for the channel's own video ignored")
+	}
+}
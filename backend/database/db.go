@@ -33,5 +33,16 @@ func (db *DB) AutoMigrate() error {
 		&models.Template{},
 		&models.Collection{},
 		&models.CollectionVideo{},
+		&models.Playlist{},
+		&models.PlaylistVideo{},
+		&models.VideoStat{},
+		&models.VideoRevision{},
+		&models.ChannelGroup{},
+		&models.ChannelGroupMember{},
+		&models.SyncRun{},
+		&models.SyncRunResult{},
+		&models.Rule{},
+		&models.WebSubSubscription{},
+		&models.TranscriptSegment{},
 	)
 }
@@ -3,12 +3,27 @@ package models
 import "time"
 
 type Channel struct {
-	ID          uint   `gorm:"primaryKey"`
-	ChannelID   string `gorm:"uniqueIndex"`
-	Name        string
-	URL         string
-	Thumbnail   string
-	Description string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID                  uint   `gorm:"primaryKey"`
+	ChannelID           string `gorm:"uniqueIndex"`
+	SourceType          string `gorm:"default:youtube"`
+	Name                string
+	URL                 string
+	Thumbnail           string
+	Description         string
+	SubscriberCount     int64
+	ChannelCreatedAt    *time.Time
+	MetadataRefreshedAt *time.Time
+	ETag                string
+	LastModified        string
+	SummaryExcludeTypes string
+	TranscriptLanguages string
+	TranscriptTrack     string
+	Paused              bool `gorm:"default:false"`
+	Muted               bool `gorm:"default:false"`
+	PollIntervalMinutes int
+	PollOverrideMinutes int
+	LastPolledAt        *time.Time
+	NextPollAt          *time.Time `gorm:"index"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type ChannelGroup struct {
+	ID           uint   `gorm:"primaryKey"`
+	Name         string `gorm:"uniqueIndex"`
+	Position     int
+	TemplateName string
+	AutoSummary  bool      `gorm:"default:true"`
+	Channels     []Channel `gorm:"many2many:channel_group_members;"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type ChannelGroupMember struct {
+	ChannelGroupID uint `gorm:"primaryKey"`
+	ChannelID      uint `gorm:"primaryKey"`
+	Position       int
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Playlist struct {
+	ID           uint   `gorm:"primaryKey"`
+	PlaylistID   string `gorm:"uniqueIndex"`
+	Title        string
+	Author       string
+	URL          string
+	LastPolledAt *time.Time
+	NextPollAt   *time.Time `gorm:"index"`
+	Videos       []Video    `gorm:"many2many:playlist_videos;"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type PlaylistVideo struct {
+	PlaylistID uint `gorm:"primaryKey"`
+	VideoID    uint `gorm:"primaryKey"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Rule matches newly ingested videos and applies actions to them. Empty
+// conditions match every video; rules run in Position order.
+type Rule struct {
+	ID       uint `gorm:"primaryKey"`
+	Name     string
+	Position int  `gorm:"index"`
+	Enabled  bool `gorm:"default:true"`
+
+	ChannelID          string
+	TitlePattern       string
+	VideoType          string
+	MinDurationSeconds int
+	MaxDurationSeconds int
+
+	Mute           bool
+	SkipSummary    bool
+	TagID          uint
+	CollectionID   uint
+	TemplateName   string
+	StopProcessing bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
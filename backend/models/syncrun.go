@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// SyncRun records one manual, tray or scheduled sync. Feeds stored outside
+// those, such as the first sync of SubscribeFeed, import initial syncs and
+// WebSub pushes, are not recorded.
+type SyncRun struct {
+	ID             uint      `gorm:"primaryKey"`
+	Trigger        string    `gorm:"index"`
+	StartedAt      time.Time `gorm:"index"`
+	FinishedAt     *time.Time
+	TotalNew       int
+	TotalUpdated   int
+	TotalUnchanged int
+	TotalFailed    int
+	Error          string
+	Results        []SyncRunResult
+}
+
+// SyncRunResult is one channel or playlist outcome within a SyncRun.
+type SyncRunResult struct {
+	ID            uint `gorm:"primaryKey"`
+	SyncRunID     uint `gorm:"index"`
+	Kind          string
+	FeedID        string `gorm:"index"`
+	Name          string
+	NewVideos     int
+	UpdatedVideos int
+	RemovedVideos int
+	Unchanged     bool
+	Error         string
+}
@@ -0,0 +1,10 @@
+package models
+
+type TranscriptSegment struct {
+	ID              uint `gorm:"primaryKey"`
+	VideoID         uint `gorm:"index"`
+	Position        int
+	StartSeconds    float64
+	DurationSeconds float64
+	Text            string
+}
@@ -3,20 +3,45 @@ package models
 import "time"
 
 type Video struct {
-	ID                    uint   `gorm:"primaryKey"`
-	VideoID               string `gorm:"uniqueIndex"`
-	Title                 string
-	URL                   string
-	ChannelID             uint
-	Transcript            string
-	TranscriptStatus      string
-	TranscriptLastError   string
-	TranscriptLastAttempt *time.Time
-	Summary               string
-	Thumbnail             string
-	PublishedAt           time.Time
-	Tags                  []Tag        `gorm:"many2many:video_tags;"`
-	Collections           []Collection `gorm:"many2many:collection_videos;"`
-	CreatedAt             time.Time
-	UpdatedAt             time.Time
+	ID                     uint   `gorm:"primaryKey"`
+	VideoID                string `gorm:"uniqueIndex"`
+	Title                  string
+	URL                    string
+	ChannelID              uint
+	Transcript             string
+	TranscriptStatus       string
+	TranscriptLastError    string
+	TranscriptLastAttempt  *time.Time
+	TranscriptAttempts     int        `gorm:"default:0"`
+	TranscriptNextAttempt  *time.Time `gorm:"index"`
+	TranscriptLanguages    string
+	TranscriptTrack        string
+	TranscriptLanguageUsed string
+	TranscriptTrackUsed    string
+	TranscriptSource       string
+	Summary                string
+	Thumbnail              string
+	Description            string
+	MediaURL               string
+	MediaType              string
+	VideoType              string `gorm:"index"`
+	DurationSeconds        int
+	Chapters               string
+	Category               string
+	Language               string
+	EnrichedAt             *time.Time
+	Muted                  bool `gorm:"default:false;index"`
+	SkipSummary            bool `gorm:"default:false"`
+	TemplateName           string
+	ViewCount              int64
+	RatingCount            int64
+	RatingAverage          float64
+	PublishedAt            time.Time
+	Removed                bool `gorm:"default:false"`
+	RemovedAt              *time.Time
+	Tags                   []Tag        `gorm:"many2many:video_tags;"`
+	Collections            []Collection `gorm:"many2many:collection_videos;"`
+	Playlists              []Playlist   `gorm:"many2many:playlist_videos;"`
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
 }
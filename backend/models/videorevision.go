@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type VideoRevision struct {
+	ID        uint `gorm:"primaryKey"`
+	VideoID   uint `gorm:"index"`
+	Field     string
+	OldValue  string
+	NewValue  string
+	ChangedAt time.Time
+}
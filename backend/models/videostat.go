@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type VideoStat struct {
+	ID            uint `gorm:"primaryKey"`
+	VideoID       uint `gorm:"index"`
+	ViewCount     int64
+	RatingCount   int64
+	RatingAverage float64
+	RecordedAt    time.Time
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// WebSubSubscription tracks the hub lease for one channel topic.
+type WebSubSubscription struct {
+	ID           uint   `gorm:"primaryKey"`
+	ChannelID    string `gorm:"uniqueIndex"`
+	Topic        string `gorm:"index"`
+	HubURL       string
+	State        string
+	LeaseSeconds int
+	ExpiresAt    *time.Time
+	LastError    string
+	LastPushAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultASRExtractCommand downloads the audio track as 16 kHz mono WAV,
+// the input format whisper.cpp expects.
+const DefaultASRExtractCommand = `{ytdlp} -f bestaudio -x --audio-format wav --postprocessor-args "ffmpeg:-ar 16000 -ac 1" -o {dir}/audio.%(ext)s {url}`
+
+// ASRConfig describes the local speech-to-text pipeline. ExtractCommand is
+// split like a shell command line after which {ytdlp}, {url}, {dir} and
+// {output} are substituted; it must leave a WAV file at {output}. The
+// whisper.cpp binary then transcribes that file on the CPU.
+type ASRConfig struct {
+	ExtractCommand     string
+	YTDLPPath          string
+	WhisperPath        string
+	ModelPath          string
+	Language           string
+	MaxDurationSeconds int
+	Concurrency        int
+}
+
+// Transcribe runs the ASR pipeline for a video whose captions are
+// unavailable. Videos longer than cfg.MaxDurationSeconds are refused before
+// anything is downloaded; an unknown duration is looked up with yt-dlp first.
+// At most cfg.Concurrency transcriptions run at once; further calls wait.
+func (s *TranscriptService) Transcribe(ctx context.Context, cfg ASRConfig, videoURL string, durationSeconds int) (Transcript, error) {
+	if strings.TrimSpace(videoURL) == "" {
+		return Transcript{}, fmt.Errorf("video url is required")
+	}
+	if strings.TrimSpace(cfg.WhisperPath) == "" || strings.TrimSpace(cfg.ModelPath) == "" {
+		return Transcript{}, fmt.Errorf("whisper binary and model are required")
+	}
+	ytdlp := strings.TrimSpace(cfg.YTDLPPath)
+	if ytdlp == "" {
+		ytdlp = defaultYTDLPBinary
+	}
+	if cfg.MaxDurationSeconds > 0 {
+		if durationSeconds <= 0 {
+			probed, err := probeDuration(ctx, ytdlp, videoURL)
+			if err != nil {
+				return Transcript{}, fmt.Errorf("video duration unknown; speech-to-text needs it for the duration limit: %w", err)
+			}
+			durationSeconds = probed
+		}
+		if durationSeconds > cfg.MaxDurationSeconds {
+			return Transcript{}, fmt.Errorf("video is longer than the speech-to-text limit (%s > %s)", FormatDuration(durationSeconds), FormatDuration(cfg.MaxDurationSeconds))
+		}
+	}
+
+	release, err := s.acquireASR(ctx, cfg.Concurrency)
+	if err != nil {
+		return Transcript{}, err
+	}
+	defer release()
+
+	// CPU transcription runs at a few times real time at best; the bound only
+	// keeps a hung process from holding its slot forever.
+	timeout := 10*time.Minute + 3*time.Duration(durationSeconds)*time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "ytfeed-asr-")
+	if err != nil {
+		return Transcript{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	audio := filepath.Join(dir, "audio.wav")
+	command := cfg.ExtractCommand
+	if strings.TrimSpace(command) == "" {
+		command = DefaultASRExtractCommand
+	}
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return Transcript{}, err
+	}
+	if len(args) == 0 {
+		return Transcript{}, fmt.Errorf("audio extraction command is empty")
+	}
+	replacer := strings.NewReplacer("{ytdlp}", ytdlp, "{url}", videoURL, "{dir}", filepath.ToSlash(dir), "{output}", filepath.ToSlash(audio))
+	for i := range args {
+		args[i] = replacer.Replace(args[i])
+	}
+	if _, err := runTool(ctx, "audio extraction", args[0], args[1:]...); err != nil {
+		return Transcript{}, markRateLimit(err)
+	}
+	if _, err := os.Stat(audio); err != nil {
+		return Transcript{}, fmt.Errorf("audio extraction produced no %s", filepath.Base(audio))
+	}
+
+	language := strings.TrimSpace(cfg.Language)
+	if language == "" {
+		language = "auto"
+	}
+	outBase := filepath.Join(dir, "transcript")
+	if _, err := runTool(ctx, "whisper", cfg.WhisperPath,
+		"-m", cfg.ModelPath,
+		"-f", audio,
+		"-l", language,
+		"-oj",
+		"-of", outBase,
+		"-np",
+	); err != nil {
+		return Transcript{}, err
+	}
+
+	raw, err := os.ReadFile(outBase + ".json")
+	if err != nil {
+		return Transcript{}, fmt.Errorf("whisper produced no output: %w", err)
+	}
+	return parseWhisperJSON(raw)
+}
+
+// probeDuration asks yt-dlp for a video's length in seconds without
+// downloading it.
+func probeDuration(ctx context.Context, ytdlp string, videoURL string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, ytdlpTimeout)
+	defer cancel()
+	out, err := runTool(ctx, "yt-dlp", ytdlp, "--print", "duration", "--skip-download", "--no-playlist", "--no-warnings", videoURL)
+	if err != nil {
+		return 0, markRateLimit(err)
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil || seconds <= 0 {
+		return 0, fmt.Errorf("yt-dlp reported no duration")
+	}
+	return int(seconds), nil
+}
+
+// acquireASR takes one of limit transcription slots. The slot pool is
+// rebuilt when the limit changes; calls holding a slot of the old pool
+// release it there.
+func (s *TranscriptService) acquireASR(ctx context.Context, limit int) (func(), error) {
+	if limit <= 0 {
+		limit = 1
+	}
+	s.asrMu.Lock()
+	if s.asrSlots == nil || cap(s.asrSlots) != limit {
+		s.asrSlots = make(chan struct{}, limit)
+	}
+	slots := s.asrSlots
+	s.asrMu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type whisperOutput struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets struct {
+			From int64 `json:"from"`
+			To   int64 `json:"to"`
+		} `json:"offsets"`
+		Text string `json:"text"`
+	} `json:"transcription"`
+}
+
+// parseWhisperJSON reads the -oj output of whisper.cpp, whose segment
+// offsets are in milliseconds.
+func parseWhisperJSON(raw []byte) (Transcript, error) {
+	var out whisperOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return Transcript{}, fmt.Errorf("decode whisper output: %w", err)
+	}
+
+	transcript := Transcript{
+		Language: out.Result.Language,
+		Source:   TranscriptSourceASR,
+		Segments: make([]TranscriptSegment, 0, len(out.Transcription)),
+	}
+	lines := make([]string, 0, len(out.Transcription))
+	for _, seg := range out.Transcription {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		transcript.Segments = append(transcript.Segments, TranscriptSegment{
+			Start:    float64(seg.Offsets.From) / 1000,
+			Duration: float64(seg.Offsets.To-seg.Offsets.From) / 1000,
+			Text:     text,
+		})
+		lines = append(lines, text)
+	}
+	if len(lines) == 0 {
+		return Transcript{}, fmt.Errorf("whisper returned an empty transcript")
+	}
+	transcript.Text = strings.Join(lines, "\n")
+	return transcript, nil
+}
+
+// splitCommandLine splits a command line on whitespace, keeping double- or
+// single-quoted parts together. Backslashes are literal so Windows paths
+// survive.
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command: %s", line)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
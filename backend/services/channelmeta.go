@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ChannelMetadata struct {
+	Thumbnail       string
+	Description     string
+	SubscriberCount int64
+	CreatedAt       *time.Time
+}
+
+var (
+	metaPropertyPattern = regexp.MustCompile(`(?i)<meta\s+(?:property|name)="(og:image|og:description|description)"\s+content="([^"]*)"`)
+	subscriberPattern   = regexp.MustCompile(`"subscriberCountText":\{[^}]*?"simpleText":"([^"]+)"`)
+	joinedDatePattern   = regexp.MustCompile(`"joinedDateText":\{"content":"Joined ([^"]+)"`)
+)
+
+// FetchChannelMetadata scrapes the public channel page. It is the fallback
+// when no Data API key is configured, so every field is best effort.
+func (s *YouTubeService) FetchChannelMetadata(ctx context.Context, channelID string) (ChannelMetadata, error) {
+	if channelID == "" {
+		return ChannelMetadata{}, fmt.Errorf("channelID is required")
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL()+"/channel/"+url.PathEscape(channelID)+"/about", nil)
+	if err != nil {
+		return ChannelMetadata{}, err
+	}
+	req.Header.Set("Accept-Language", "en")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ChannelMetadata{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return ChannelMetadata{}, fmt.Errorf("channel page request failed: status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return ChannelMetadata{}, err
+	}
+	return parseChannelPage(string(body)), nil
+}
+
+func (s *YouTubeDataService) ChannelMetadata(ctx context.Context, apiKey string, channelID string) (ChannelMetadata, error) {
+	if channelID == "" {
+		return ChannelMetadata{}, fmt.Errorf("channelID is required")
+	}
+	params := url.Values{}
+	params.Set("part", "snippet,statistics")
+	params.Set("id", channelID)
+
+	var out struct {
+		Items []struct {
+			Snippet struct {
+				Description string       `json:"description"`
+				PublishedAt string       `json:"publishedAt"`
+				Thumbnails  apiThumbnail `json:"thumbnails"`
+			} `json:"snippet"`
+			Statistics struct {
+				SubscriberCount string `json:"subscriberCount"`
+			} `json:"statistics"`
+		} `json:"items"`
+	}
+	if err := s.get(ctx, apiKey, "/channels", params, &out); err != nil {
+		return ChannelMetadata{}, err
+	}
+	if len(out.Items) == 0 {
+		return ChannelMetadata{}, fmt.Errorf("channel %s not found", channelID)
+	}
+
+	item := out.Items[0]
+	meta := ChannelMetadata{
+		Thumbnail:   item.Snippet.Thumbnails.best(),
+		Description: strings.TrimSpace(item.Snippet.Description),
+	}
+	meta.SubscriberCount, _ = strconv.ParseInt(item.Statistics.SubscriberCount, 10, 64)
+	if t, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt); err == nil {
+		meta.CreatedAt = &t
+	}
+	return meta, nil
+}
+
+func parseChannelPage(body string) ChannelMetadata {
+	meta := ChannelMetadata{}
+	for _, m := range metaPropertyPattern.FindAllStringSubmatch(body, -1) {
+		value := strings.TrimSpace(html.UnescapeString(m[2]))
+		switch strings.ToLower(m[1]) {
+		case "og:image":
+			if meta.Thumbnail == "" {
+				meta.Thumbnail = value
+			}
+		case "og:description", "description":
+			if meta.Description == "" {
+				meta.Description = value
+			}
+		}
+	}
+	if m := subscriberPattern.FindStringSubmatch(body); m != nil {
+		meta.SubscriberCount = parseAbbreviatedCount(m[1])
+	}
+	if m := joinedDatePattern.FindStringSubmatch(body); m != nil {
+		if t, err := time.Parse("Jan 2, 2006", m[1]); err == nil {
+			meta.CreatedAt = &t
+		}
+	}
+	return meta
+}
+
+// parseAbbreviatedCount turns "1.23M subscribers" into 1230000.
+func parseAbbreviatedCount(text string) int64 {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0
+	}
+	num := strings.ReplaceAll(fields[0], ",", "")
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(num, "K"):
+		multiplier = 1e3
+		num = strings.TrimSuffix(num, "K")
+	case strings.HasSuffix(num, "M"):
+		multiplier = 1e6
+		num = strings.TrimSuffix(num, "M")
+	case strings.HasSuffix(num, "B"):
+		multiplier = 1e9
+		num = strings.TrimSuffix(num, "B")
+	}
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(value * multiplier)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	VideoTypeRegular  = "regular"
+	VideoTypeShort    = "short"
+	VideoTypeLive     = "live"
+	VideoTypePremiere = "premiere"
+)
+
+func IsValidVideoType(t string) bool {
+	switch t {
+	case VideoTypeRegular, VideoTypeShort, VideoTypeLive, VideoTypePremiere:
+		return true
+	default:
+		return false
+	}
+}
+
+// ClassifyVideo inspects the watch page player metadata. Streams carry
+// isLiveContent; premieres have liveBroadcastDetails without it.
+func (s *YouTubeService) ClassifyVideo(ctx context.Context, videoID string) (string, error) {
+	if videoID == "" {
+		return "", fmt.Errorf("videoID is required")
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL()+"/watch?v="+url.QueryEscape(videoID), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept-Language", "en")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("watch page request failed: status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return "", err
+	}
+	return classifyWatchPage(string(body)), nil
+}
+
+func classifyWatchPage(body string) string {
+	compact := strings.ReplaceAll(body, " ", "")
+	switch {
+	case strings.Contains(compact, `"isLiveContent":true`):
+		return VideoTypeLive
+	case strings.Contains(compact, `"liveBroadcastDetails":{`):
+		return VideoTypePremiere
+	case strings.Contains(compact, `<linkrel="canonical"href="https://www.youtube.com/shorts/`):
+		return VideoTypeShort
+	default:
+		return VideoTypeRegular
+	}
+}
+
+func classifyByLink(link string) string {
+	if strings.Contains(link, "/shorts/") {
+		return VideoTypeShort
+	}
+	if strings.Contains(link, "/live/") {
+		return VideoTypeLive
+	}
+	return ""
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package services
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideConsole keeps helper binaries from flashing a console window.
+func hideConsole(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: 0x08000000}
+}
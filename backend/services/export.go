@@ -49,6 +49,24 @@ func (s *ExportService) ExportPDF(ctx context.Context, content string, baseDir s
 	return path, nil
 }
 
+func (s *ExportService) ExportOPML(ctx context.Context, content string, baseDir string, filename string) (string, error) {
+	_ = ctx
+	if filename == "" {
+		filename = fmt.Sprintf("subscriptions-%s.opml", time.Now().Format("20060102-150405"))
+	}
+	if baseDir == "" {
+		baseDir = "exports"
+	}
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(baseDir, filename)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 func buildSimplePDF(content string) []byte {
 	lines := wrapTextLines(content, 92)
 	var stream strings.Builder
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	SourceYouTube = "youtube"
+	SourceRSS     = "rss"
+)
+
+// FeedSource is a subscribable feed provider. Resolve turns user input into
+// the stable ID stored as models.Channel.ChannelID; FetchFeed polls it.
+type FeedSource interface {
+	Type() string
+	Resolve(ctx context.Context, input string) (string, error)
+	FetchFeed(ctx context.Context, id string, cache FeedCache) (*Feed, error)
+}
+
+type Feed struct {
+	ChannelID   string
+	ChannelName string
+	ChannelURL  string
+	Entries     []FeedEntry
+	NotModified bool
+	Cache       FeedCache
+}
+
+type FeedCache struct {
+	ETag         string
+	LastModified string
+}
+
+type FeedEntry struct {
+	VideoID     string
+	ChannelID   string
+	Title       string
+	URL         string
+	Thumbnail   string
+	Description string
+	Views       int64
+	RatingCount int64
+	Rating      float64
+	VideoType   string
+	MediaURL    string
+	MediaType   string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+	// Metadata is set when the entry was enriched before it was stored.
+	Metadata *VideoMetadata
+}
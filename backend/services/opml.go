@@ -0,0 +1,117 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type OPMLFeed struct {
+	Title   string
+	XMLURL  string
+	HTMLURL string
+	Folder  string
+}
+
+type OPMLFolder struct {
+	Name  string
+	Feeds []OPMLFeed
+}
+
+type OPMLService struct{}
+
+// Parse flattens the outline tree into feeds, tagging each with the name of
+// the nearest enclosing folder outline.
+func (s *OPMLService) Parse(r io.Reader) ([]OPMLFeed, error) {
+	var doc opmlDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid opml: %w", err)
+	}
+	var feeds []OPMLFeed
+	collectOPMLFeeds(doc.Body.Outlines, "", &feeds)
+	return feeds, nil
+}
+
+func (s *OPMLService) Build(title string, ungrouped []OPMLFeed, folders []OPMLFolder) ([]byte, error) {
+	doc := opmlDocument{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       title,
+			DateCreated: time.Now().Format(time.RFC1123Z),
+		},
+	}
+	for _, folder := range folders {
+		outline := opmlOutline{Text: folder.Name, Title: folder.Name}
+		for _, feed := range folder.Feeds {
+			outline.Outlines = append(outline.Outlines, feedOutline(feed))
+		}
+		doc.Body.Outlines = append(doc.Body.Outlines, outline)
+	}
+	for _, feed := range ungrouped {
+		doc.Body.Outlines = append(doc.Body.Outlines, feedOutline(feed))
+	}
+
+	raw, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), raw...), nil
+}
+
+func collectOPMLFeeds(outlines []opmlOutline, folder string, out *[]OPMLFeed) {
+	for _, o := range outlines {
+		title := strings.TrimSpace(o.Title)
+		if title == "" {
+			title = strings.TrimSpace(o.Text)
+		}
+		if strings.TrimSpace(o.XMLURL) != "" {
+			*out = append(*out, OPMLFeed{
+				Title:   title,
+				XMLURL:  strings.TrimSpace(o.XMLURL),
+				HTMLURL: strings.TrimSpace(o.HTMLURL),
+				Folder:  folder,
+			})
+			continue
+		}
+		if len(o.Outlines) > 0 {
+			collectOPMLFeeds(o.Outlines, title, out)
+		}
+	}
+}
+
+func feedOutline(feed OPMLFeed) opmlOutline {
+	return opmlOutline{
+		Text:    feed.Title,
+		Title:   feed.Title,
+		Type:    "rss",
+		XMLURL:  feed.XMLURL,
+		HTMLURL: feed.HTMLURL,
+	}
+}
+
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const defaultYouTubeBaseURL = "https://www.youtube.com"
+
+var (
+	channelIDPattern    = regexp.MustCompile(`^UC[0-9A-Za-z_-]{22}$`)
+	channelPathPattern  = regexp.MustCompile(`/channel/(UC[0-9A-Za-z_-]{22})`)
+	channelQueryPattern = regexp.MustCompile(`channel_id=(UC[0-9A-Za-z_-]{22})`)
+	linkTagPattern      = regexp.MustCompile(`(?i)<link\s[^>]*>`)
+	metaTagPattern      = regexp.MustCompile(`(?i)<meta\s[^>]*>`)
+	attrPattern         = regexp.MustCompile(`(?i)([a-z]+)\s*=\s*"([^"]*)"`)
+	playlistIDPattern   = regexp.MustCompile(`^[0-9A-Za-z_-]{10,}$`)
+)
+
+// ResolveChannelID turns a channel ID, @handle, channel/handle/legacy URL or
+// video URL into a canonical UC... channel ID.
+func (s *YouTubeService) ResolveChannelID(ctx context.Context, input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("channel input is required")
+	}
+	if channelIDPattern.MatchString(input) {
+		return input, nil
+	}
+	if m := channelPathPattern.FindStringSubmatch(input); m != nil {
+		return m[1], nil
+	}
+	if m := channelQueryPattern.FindStringSubmatch(input); m != nil {
+		return m[1], nil
+	}
+
+	path, err := channelPagePath(input)
+	if err != nil {
+		return "", err
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL()+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept-Language", "en")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("channel page request failed: status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return "", err
+	}
+
+	id := extractChannelIDFromPage(string(body))
+	if id == "" {
+		return "", fmt.Errorf("could not resolve channel id from %s", input)
+	}
+	return id, nil
+}
+
+func (s *YouTubeService) baseURL() string {
+	base := strings.TrimRight(strings.TrimSpace(s.BaseURL), "/")
+	if base == "" {
+		return defaultYouTubeBaseURL
+	}
+	return base
+}
+
+// channelPagePath maps user input to a path on the YouTube site whose HTML
+// carries the owning channel's canonical or RSS link.
+func channelPagePath(input string) (string, error) {
+	if strings.HasPrefix(input, "@") {
+		return "/" + url.PathEscape(input), nil
+	}
+
+	raw := input
+	if !strings.HasPrefix(raw, "http://") && !strings.HasPrefix(raw, "https://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid channel input: %s", input)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "youtu.be" {
+		id := strings.Trim(u.Path, "/")
+		if id == "" {
+			return "", fmt.Errorf("invalid video url: %s", input)
+		}
+		return "/watch?v=" + url.QueryEscape(id), nil
+	}
+	if host != "youtube.com" && !strings.HasSuffix(host, ".youtube.com") {
+		return "", fmt.Errorf("unsupported channel input: %s", input)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) == 0 || segments[0] == "" {
+		return "", fmt.Errorf("invalid channel url: %s", input)
+	}
+	switch {
+	case strings.HasPrefix(segments[0], "@"):
+		return "/" + segments[0], nil
+	case segments[0] == "c" || segments[0] == "user":
+		if len(segments) < 2 || segments[1] == "" {
+			return "", fmt.Errorf("invalid channel url: %s", input)
+		}
+		return "/" + segments[0] + "/" + segments[1], nil
+	case segments[0] == "watch":
+		id := u.Query().Get("v")
+		if id == "" {
+			return "", fmt.Errorf("invalid video url: %s", input)
+		}
+		return "/watch?v=" + url.QueryEscape(id), nil
+	case segments[0] == "shorts" || segments[0] == "live" || segments[0] == "embed":
+		if len(segments) < 2 || segments[1] == "" {
+			return "", fmt.Errorf("invalid video url: %s", input)
+		}
+		return "/watch?v=" + url.QueryEscape(segments[1]), nil
+	default:
+		return "", fmt.Errorf("unsupported channel url: %s", input)
+	}
+}
+
+func extractChannelIDFromPage(body string) string {
+	for _, tag := range linkTagPattern.FindAllString(body, -1) {
+		attrs := tagAttributes(tag)
+		rel := strings.ToLower(attrs["rel"])
+		href := attrs["href"]
+		switch {
+		case rel == "alternate" && strings.Contains(strings.ToLower(attrs["type"]), "rss"):
+			if m := channelQueryPattern.FindStringSubmatch(href); m != nil {
+				return m[1]
+			}
+		case rel == "canonical":
+			if m := channelPathPattern.FindStringSubmatch(href); m != nil {
+				return m[1]
+			}
+		}
+	}
+	for _, tag := range metaTagPattern.FindAllString(body, -1) {
+		attrs := tagAttributes(tag)
+		switch strings.ToLower(attrs["itemprop"]) {
+		case "channelid", "identifier":
+			if content := strings.TrimSpace(attrs["content"]); channelIDPattern.MatchString(content) {
+				return content
+			}
+		}
+	}
+	return ""
+}
+
+// tagAttributes reads the double-quoted attributes of an HTML tag in any
+// order, with lower-cased names.
+func tagAttributes(tag string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range attrPattern.FindAllStringSubmatch(tag, -1) {
+		attrs[strings.ToLower(m[1])] = m[2]
+	}
+	return attrs
+}
+
+func ExtractPlaylistID(input string) string {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return ""
+	}
+	if strings.Contains(input, "list=") {
+		raw := input
+		if !strings.HasPrefix(raw, "http://") && !strings.HasPrefix(raw, "https://") {
+			raw = "https://" + raw
+		}
+		if u, err := url.Parse(raw); err == nil {
+			if id := u.Query().Get("list"); id != "" {
+				return id
+			}
+		}
+		return ""
+	}
+	if playlistIDPattern.MatchString(input) {
+		return input
+	}
+	return ""
+}
+
+func ChannelIDFromFeedURL(feedURL string) string {
+	if m := channelQueryPattern.FindStringSubmatch(feedURL); m != nil {
+		return m[1]
+	}
+	if m := channelPathPattern.FindStringSubmatch(feedURL); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// PlaylistIDFromFeedURL returns the playlist of a YouTube
+// feeds/videos.xml?playlist_id=... URL.
+func PlaylistIDFromFeedURL(feedURL string) string {
+	u, err := url.Parse(strings.TrimSpace(feedURL))
+	if err != nil || !IsYouTubeURL(feedURL) {
+		return ""
+	}
+	id := u.Query().Get("playlist_id")
+	if !playlistIDPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// IsYouTubeURL reports whether input points at youtube.com or one of its
+// subdomains.
+func IsYouTubeURL(input string) bool {
+	u, err := url.Parse(strings.TrimSpace(input))
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "youtube.com" || strings.HasSuffix(host, ".youtube.com")
+}
+
+func IsChannelID(input string) bool {
+	return channelIDPattern.MatchString(input)
+}
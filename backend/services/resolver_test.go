@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	canonicalChannelID = "UCcanonical0123456789abc"
+	rssChannelID       = "UCrss_link-0123456789abc"
+	metaChannelID      = "UCmeta_tag-0123456789abc"
+	videoChannelID     = "UCvideo_own0123456789abc"
+)
+
+// newChannelPageStandIn serves the pages ResolveChannelID reads, each
+// carrying the channel ID in a different place, and records the requested
+// URIs.
+func newChannelPageStandIn(t *testing.T, requested *[]string) *httptest.Server {
+	t.Helper()
+	pages := map[string]string{
+		"/@handle": `<html><head><link rel="canonical" href="https://www.youtube.com/channel/` + canonicalChannelID + `"></head></html>`,
+		"/c/Custom": `<html><head><link rel="alternate" type="application/rss+xml" title="RSS" ` +
+			`href="https://www.youtube.com/feeds/videos.xml?channel_id=` + rssChannelID + `"></head></html>`,
+		"/user/legacy": `<html><body><meta itemprop="identifier" content="` + metaChannelID + `"></body></html>`,
+		// Video pages name the owner with content before itemprop.
+		"/watch?v=vid123": `<html><body><meta content="` + videoChannelID + `" itemprop="channelId"></body></html>`,
+		"/@nothing":       `<html><head><link rel="canonical" href="https://www.youtube.com/@nothing"></head></html>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requested = append(*requested, r.URL.RequestURI())
+		page, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResolveChannelID(t *testing.T) {
+	var requested []string
+	server := newChannelPageStandIn(t, &requested)
+	service := &YouTubeService{BaseURL: server.URL}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"@handle", canonicalChannelID},
+		{"https://www.youtube.com/@handle/videos", canonicalChannelID},
+		{"youtube.com/c/Custom", rssChannelID},
+		{"https://www.youtube.com/user/legacy", metaChannelID},
+		{"https://www.youtube.com/watch?v=vid123&t=42s", videoChannelID},
+		{"https://youtube.com/shorts/vid123", videoChannelID},
+		{"https://youtu.be/vid123?si=share", videoChannelID},
+		{"https://m.youtube.com/live/vid123", videoChannelID},
+	}
+	for _, tt := range tests {
+		got, err := service.ResolveChannelID(context.Background(), tt.input)
+		if err != nil {
+			t.Errorf("ResolveChannelID(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveChannelID(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestResolveChannelIDWithoutFetching(t *testing.T) {
+	var requested []string
+	server := newChannelPageStandIn(t, &requested)
+	service := &YouTubeService{BaseURL: server.URL}
+
+	for _, input := range []string{
+		canonicalChannelID,
+		"https://www.youtube.com/channel/" + canonicalChannelID + "/videos",
+		"https://www.youtube.com/feeds/videos.xml?channel_id=" + canonicalChannelID,
+	} {
+		got, err := service.ResolveChannelID(context.Background(), input)
+		if err != nil || got != canonicalChannelID {
+			t.Errorf("ResolveChannelID(%q) = %q, %v", input, got, err)
+		}
+	}
+	if len(requested) != 0 {
+		t.Fatalf("channel IDs in the input still fetched %v", requested)
+	}
+}
+
+func TestResolveChannelIDErrors(t *testing.T) {
+	var requested []string
+	server := newChannelPageStandIn(t, &requested)
+	service := &YouTubeService{BaseURL: server.URL}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "required"},
+		{"https://example.com/@handle", "unsupported"},
+		{"https://www.youtube.com/playlist?list=PL123", "unsupported"},
+		{"https://www.youtube.com/c/", "invalid"},
+		{"@nothing", "could not resolve"},
+		{"@missing", "status 404"},
+	}
+	for _, tt := range tests {
+		_, err := service.ResolveChannelID(context.Background(), tt.input)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ResolveChannelID(%q) error = %v, want one mentioning %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestExtractChannelIDFromPageAttributeOrder(t *testing.T) {
+	pages := []string{
+		`<meta itemprop="channelId" content="` + metaChannelID + `">`,
+		`<meta content="` + metaChannelID + `" itemprop="channelId">`,
+		`<META CONTENT="` + metaChannelID + `" ITEMPROP="identifier" />`,
+		`<link href="https://www.youtube.com/feeds/videos.xml?channel_id=` + metaChannelID + `" type="application/rss+xml" rel="alternate">`,
+		`<link href="https://www.youtube.com/channel/` + metaChannelID + `" rel="canonical">`,
+	}
+	for _, page := range pages {
+		if got := extractChannelIDFromPage(page); got != metaChannelID {
+			t.Errorf("extractChannelIDFromPage(%s) = %q", page, got)
+		}
+	}
+	if got := extractChannelIDFromPage(`<meta itemprop="name" content="` + metaChannelID + `">`); got != "" {
+		t.Errorf("unrelated meta tag matched: %q", got)
+	}
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RSSFeedService polls generic RSS 2.0 and Atom feeds such as podcasts and
+// conference talk feeds. Feeds are identified by their URL.
+type RSSFeedService struct {
+	Client *http.Client
+}
+
+func (s *RSSFeedService) Type() string {
+	return SourceRSS
+}
+
+func (s *RSSFeedService) Resolve(ctx context.Context, input string) (string, error) {
+	_ = ctx
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("feed url is required")
+	}
+	if !strings.HasPrefix(input, "http://") && !strings.HasPrefix(input, "https://") {
+		input = "https://" + input
+	}
+	u, err := url.Parse(input)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid feed url: %s", input)
+	}
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+func (s *RSSFeedService) FetchFeed(ctx context.Context, feedURL string, cache FeedCache) (*Feed, error) {
+	if feedURL == "" {
+		return nil, fmt.Errorf("feed url is required")
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &Feed{ChannelID: feedURL, NotModified: true, Cache: cache}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("feed request failed: status %d", resp.StatusCode)
+	}
+
+	var raw genericFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{
+		ChannelID: feedURL,
+		Cache: FeedCache{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}
+	switch raw.XMLName.Local {
+	case "rss":
+		feed.ChannelName = strings.TrimSpace(raw.Channel.Title)
+		feed.ChannelURL = rssLink(raw.Channel.Links)
+		feed.Entries = rssEntries(feedURL, raw.Channel)
+	case "feed":
+		feed.ChannelName = strings.TrimSpace(raw.Title)
+		feed.ChannelURL = atomLink(raw.Links, "alternate")
+		feed.Entries = atomEntries(feedURL, raw.Entries)
+	default:
+		return nil, fmt.Errorf("unsupported feed format: %s", raw.XMLName.Local)
+	}
+	if feed.ChannelURL == "" {
+		feed.ChannelURL = feedURL
+	}
+	return feed, nil
+}
+
+func rssEntries(feedURL string, ch rssChannel) []FeedEntry {
+	fallbackImage := ch.ITunesImage.Href
+	if fallbackImage == "" {
+		fallbackImage = ch.Image.URL
+	}
+
+	entries := make([]FeedEntry, 0, len(ch.Items))
+	for _, item := range ch.Items {
+		link := rssLink(item.Links)
+		key := strings.TrimSpace(item.GUID)
+		if key == "" {
+			key = link
+		}
+		if key == "" {
+			key = strings.TrimSpace(item.Enclosure.URL)
+		}
+		if key == "" {
+			continue
+		}
+
+		if link == "" {
+			link = item.Enclosure.URL
+		}
+		thumbnail := item.ITunesImage.Href
+		if thumbnail == "" {
+			thumbnail = item.MediaThumbnail.URL
+		}
+		if thumbnail == "" {
+			thumbnail = fallbackImage
+		}
+		publishedAt := parseFeedTime(item.PubDate)
+
+		entries = append(entries, FeedEntry{
+			VideoID:     feedItemID(feedURL, key),
+			ChannelID:   feedURL,
+			Title:       strings.TrimSpace(item.Title),
+			URL:         link,
+			Thumbnail:   thumbnail,
+			Description: strings.TrimSpace(item.Description),
+			MediaURL:    item.Enclosure.URL,
+			MediaType:   item.Enclosure.Type,
+			PublishedAt: publishedAt,
+			UpdatedAt:   publishedAt,
+		})
+	}
+	return entries
+}
+
+func atomEntries(feedURL string, raw []atomEntry) []FeedEntry {
+	entries := make([]FeedEntry, 0, len(raw))
+	for _, entry := range raw {
+		key := strings.TrimSpace(entry.ID)
+		link := atomLink(entry.Links, "alternate")
+		if key == "" {
+			key = link
+		}
+		if key == "" {
+			continue
+		}
+
+		mediaURL := ""
+		mediaType := ""
+		for _, l := range entry.Links {
+			if l.Rel == "enclosure" {
+				mediaURL = l.Href
+				mediaType = l.Type
+				break
+			}
+		}
+		description := strings.TrimSpace(entry.Summary)
+		if description == "" {
+			description = strings.TrimSpace(entry.Content)
+		}
+		publishedAt := parseFeedTime(entry.Published)
+		updatedAt := parseFeedTime(entry.Updated)
+		if publishedAt.IsZero() {
+			publishedAt = updatedAt
+		}
+		if updatedAt.IsZero() {
+			updatedAt = publishedAt
+		}
+
+		entries = append(entries, FeedEntry{
+			VideoID:     feedItemID(feedURL, key),
+			ChannelID:   feedURL,
+			Title:       strings.TrimSpace(entry.Title),
+			URL:         link,
+			Thumbnail:   entry.MediaThumbnail.URL,
+			Description: description,
+			MediaURL:    mediaURL,
+			MediaType:   mediaType,
+			PublishedAt: publishedAt,
+			UpdatedAt:   updatedAt,
+		})
+	}
+	return entries
+}
+
+// feedItemID derives a stable video_id for a feed item. The prefix keeps it
+// from ever colliding with an 11-character YouTube ID.
+func feedItemID(feedURL, key string) string {
+	sum := sha1.Sum([]byte(feedURL + "|" + key))
+	return "rss:" + hex.EncodeToString(sum[:10])
+}
+
+func atomLink(links []atomLinkElem, rel string) string {
+	for _, l := range links {
+		if l.Rel == rel || (rel == "alternate" && l.Rel == "") {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	return ""
+}
+
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+}
+
+func parseFeedTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+type genericFeed struct {
+	XMLName xml.Name
+	Channel rssChannel     `xml:"channel"`
+	Title   string         `xml:"title"`
+	Links   []atomLinkElem `xml:"link"`
+	Entries []atomEntry    `xml:"entry"`
+}
+
+// ITunesImage must precede Image because an unqualified tag matches
+// elements in any namespace.
+type rssChannel struct {
+	Title       string      `xml:"title"`
+	Links       []string    `xml:"link"`
+	ITunesImage itunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	Image       rssImage    `xml:"image"`
+	Items       []rssItem   `xml:"item"`
+}
+
+// rssLink returns the first link with text. The unqualified link tag also
+// matches <atom:link rel="self" href="..."/>, which has none.
+func rssLink(links []string) string {
+	for _, link := range links {
+		if link = strings.TrimSpace(link); link != "" {
+			return link
+		}
+	}
+	return ""
+}
+
+type rssImage struct {
+	URL string `xml:"url"`
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type rssItem struct {
+	Title          string       `xml:"title"`
+	Links          []string     `xml:"link"`
+	GUID           string       `xml:"guid"`
+	PubDate        string       `xml:"pubDate"`
+	Description    string       `xml:"description"`
+	Enclosure      rssEnclosure `xml:"enclosure"`
+	ITunesImage    itunesImage  `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	MediaThumbnail ytThumbnail  `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type rssEnclosure struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type atomEntry struct {
+	ID             string         `xml:"id"`
+	Title          string         `xml:"title"`
+	Links          []atomLinkElem `xml:"link"`
+	Published      string         `xml:"published"`
+	Updated        string         `xml:"updated"`
+	Summary        string         `xml:"summary"`
+	Content        string         `xml:"content"`
+	MediaThumbnail ytThumbnail    `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type atomLinkElem struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
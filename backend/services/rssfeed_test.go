@@ -0,0 +1,38 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRSSFeedLinksBesideAtomSelfLinks(t *testing.T) {
+	const body = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+  <title>Example</title>
+  <link>https://ex.com/</link>
+  <atom:link href="https://ex.com/feed.xml" rel="self" type="application/rss+xml"/>
+  <item>
+    <title>Episode</title>
+    <link>https://ex.com/episode-1</link>
+    <atom:link href="https://ex.com/episode-1/feed" rel="self"/>
+  </item>
+</channel>
+</rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	feed, err := (&RSSFeedService{}).FetchFeed(t.Context(), server.URL, FeedCache{})
+	if err != nil {
+		t.Fatalf("FetchFeed: %v", err)
+	}
+	if feed.ChannelURL != "https://ex.com/" {
+		t.Fatalf("ChannelURL = %q", feed.ChannelURL)
+	}
+	if len(feed.Entries) != 1 || feed.Entries[0].URL != "https://ex.com/episode-1" {
+		t.Fatalf("entries = %+v", feed.Entries)
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SubscriptionFormatTakeout  = "takeout"
+	SubscriptionFormatNewPipe  = "newpipe"
+	SubscriptionFormatFreeTube = "freetube"
+)
+
+type ImportedSubscription struct {
+	ChannelID string
+	Name      string
+	Group     string
+}
+
+type SubscriptionImportService struct{}
+
+func (s *SubscriptionImportService) DetectFormat(path string, raw []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return SubscriptionFormatTakeout
+	case ".db":
+		return SubscriptionFormatFreeTube
+	case ".json":
+		return SubscriptionFormatNewPipe
+	}
+	trimmed := bytes.TrimSpace(raw)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if bytes.Contains(trimmed, []byte(`"app_version"`)) || bytes.Contains(trimmed, []byte(`"service_id"`)) {
+			return SubscriptionFormatNewPipe
+		}
+		return SubscriptionFormatFreeTube
+	}
+	return SubscriptionFormatTakeout
+}
+
+func (s *SubscriptionImportService) Parse(format string, raw []byte) ([]ImportedSubscription, int, error) {
+	switch format {
+	case SubscriptionFormatTakeout:
+		return parseTakeoutCSV(raw)
+	case SubscriptionFormatNewPipe:
+		return parseNewPipeJSON(raw)
+	case SubscriptionFormatFreeTube:
+		return parseFreeTubeDB(raw)
+	default:
+		return nil, 0, fmt.Errorf("unsupported subscription format: %s", format)
+	}
+}
+
+// parseTakeoutCSV reads Google Takeout's subscriptions.csv. Header names are
+// localized, so rows are matched by position: id, url, title.
+func parseTakeoutCSV(raw []byte) ([]ImportedSubscription, int, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(raw, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+
+	var out []ImportedSubscription
+	invalid := 0
+	header := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid takeout csv: %w", err)
+		}
+		if len(record) == 0 || strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		id := strings.TrimSpace(record[0])
+		if !channelIDPattern.MatchString(id) {
+			id = ""
+			if len(record) > 1 {
+				id = ChannelIDFromFeedURL(record[1])
+			}
+		}
+		if id == "" {
+			if !header {
+				invalid++
+			}
+			header = false
+			continue
+		}
+		header = false
+		name := ""
+		if len(record) > 2 {
+			name = strings.TrimSpace(record[2])
+		}
+		out = append(out, ImportedSubscription{ChannelID: id, Name: name})
+	}
+	return out, invalid, nil
+}
+
+func parseNewPipeJSON(raw []byte) ([]ImportedSubscription, int, error) {
+	var doc struct {
+		Subscriptions []struct {
+			ServiceID int    `json:"service_id"`
+			URL       string `json:"url"`
+			Name      string `json:"name"`
+		} `json:"subscriptions"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, 0, fmt.Errorf("invalid newpipe json: %w", err)
+	}
+
+	var out []ImportedSubscription
+	invalid := 0
+	for _, sub := range doc.Subscriptions {
+		// service_id 0 is YouTube; other services are not supported.
+		id := ChannelIDFromFeedURL(sub.URL)
+		if sub.ServiceID != 0 || id == "" {
+			invalid++
+			continue
+		}
+		out = append(out, ImportedSubscription{ChannelID: id, Name: strings.TrimSpace(sub.Name)})
+	}
+	return out, invalid, nil
+}
+
+// parseFreeTubeDB reads a FreeTube profile export, which is one JSON profile
+// per line. Profiles other than "All Channels" become groups.
+func parseFreeTubeDB(raw []byte) ([]ImportedSubscription, int, error) {
+	type freeTubeProfile struct {
+		ID            string `json:"_id"`
+		Name          string `json:"name"`
+		Subscriptions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"subscriptions"`
+	}
+
+	var out []ImportedSubscription
+	invalid := 0
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	parsed := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var profile freeTubeProfile
+		if err := json.Unmarshal(line, &profile); err != nil {
+			invalid++
+			continue
+		}
+		parsed++
+		group := ""
+		if profile.ID != "allChannels" {
+			group = strings.TrimSpace(profile.Name)
+		}
+		for _, sub := range profile.Subscriptions {
+			id := strings.TrimSpace(sub.ID)
+			if !channelIDPattern.MatchString(id) {
+				invalid++
+				continue
+			}
+			out = append(out, ImportedSubscription{ChannelID: id, Name: strings.TrimSpace(sub.Name), Group: group})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	if parsed == 0 {
+		return nil, 0, fmt.Errorf("invalid freetube profile export")
+	}
+	return out, invalid, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ThumbnailRoutePrefix = "/thumbs/"
+	maxThumbnailBytes    = 2 << 20
+)
+
+var thumbFilePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ThumbnailCache keeps video thumbnails on disk so the feed renders offline
+// without hot-linking. Files are named after the video ID and evicted least
+// recently used first once the cache grows past its size limit.
+type ThumbnailCache struct {
+	Client *http.Client
+	Dir    string
+
+	mu       sync.Mutex
+	maxBytes int64
+}
+
+// SetMaxBytes sets the cache size limit and evicts down to it; 0 disables
+// eviction.
+func (c *ThumbnailCache) SetMaxBytes(maxBytes int64) error {
+	c.mu.Lock()
+	c.maxBytes = maxBytes
+	c.mu.Unlock()
+	return c.evict()
+}
+
+// Fetch downloads the thumbnail for videoID unless it is already cached.
+func (c *ThumbnailCache) Fetch(ctx context.Context, videoID string, thumbnailURL string) error {
+	name, ok := thumbFileName(videoID)
+	if !ok {
+		return fmt.Errorf("invalid video id: %s", videoID)
+	}
+	if strings.TrimSpace(thumbnailURL) == "" {
+		return nil
+	}
+	path := filepath.Join(c.Dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, thumbnailURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("thumbnail request failed: status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxThumbnailBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxThumbnailBytes {
+		return fmt.Errorf("thumbnail too large: %s", videoID)
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	// Concurrent syncs can fetch the same video, so each writes its own
+	// temp file and the last rename wins.
+	tmp, err := os.CreateTemp(c.Dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return c.evict()
+}
+
+// Remove drops the cached file, e.g. after the thumbnail URL changed.
+func (c *ThumbnailCache) Remove(videoID string) {
+	if name, ok := thumbFileName(videoID); ok {
+		_ = os.Remove(filepath.Join(c.Dir, name))
+	}
+}
+
+// LocalURL returns the asset route for a cached thumbnail, or "" when the
+// thumbnail is not cached.
+func (c *ThumbnailCache) LocalURL(videoID string) string {
+	name, ok := thumbFileName(videoID)
+	if !ok {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(c.Dir, name)); err != nil {
+		return ""
+	}
+	return ThumbnailRoutePrefix + name
+}
+
+// Middleware serves ThumbnailRoutePrefix from the cache and passes every
+// other request on to next. It fits application.AssetOptions.Middleware.
+func (c *ThumbnailCache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, ThumbnailRoutePrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, ThumbnailRoutePrefix)
+		if !thumbFilePattern.MatchString(strings.TrimSuffix(name, ".jpg")) || !strings.HasSuffix(name, ".jpg") {
+			http.NotFound(w, r)
+			return
+		}
+		path := filepath.Join(c.Dir, name)
+		if _, err := os.Stat(path); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		// The modification time doubles as the last access time for eviction.
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+		w.Header().Set("Cache-Control", "max-age=86400")
+		http.ServeFile(w, r, path)
+	})
+}
+
+func (c *ThumbnailCache) evict() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxBytes <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(c.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	type cached struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	files := make([]cached, 0, len(entries))
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jpg") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cached{path: filepath.Join(c.Dir, entry.Name()), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+	if total <= c.maxBytes {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+	return nil
+}
+
+// thumbFileName maps a video ID to its cache file name. Feed item IDs such
+// as "rss:<hash>" contain a colon, which is not valid in Windows file names.
+func thumbFileName(videoID string) (string, bool) {
+	base := strings.ReplaceAll(strings.TrimSpace(videoID), ":", "_")
+	if !thumbFilePattern.MatchString(base) {
+		return "", false
+	}
+	return base + ".jpg", true
+}
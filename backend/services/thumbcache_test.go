@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestThumbnailCacheSetMaxBytesEvicts(t *testing.T) {
+	cache := &ThumbnailCache{Dir: t.TempDir()}
+	old := time.Now().Add(-time.Hour)
+	for i, name := range []string{"older", "newer"} {
+		path := filepath.Join(cache.Dir, name+".jpg")
+		if err := os.WriteFile(path, make([]byte, 1024), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := old.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := cache.SetMaxBytes(1024); err != nil {
+		t.Fatalf("SetMaxBytes: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cache.Dir, "older.jpg")); !os.IsNotExist(err) {
+		t.Fatal("least recently used thumbnail kept after lowering the limit")
+	}
+	if _, err := os.Stat(filepath.Join(cache.Dir, "newer.jpg")); err != nil {
+		t.Fatalf("newest thumbnail evicted: %v", err)
+	}
+}
+
+func TestThumbnailCacheSetMaxBytesWithoutDir(t *testing.T) {
+	cache := &ThumbnailCache{Dir: filepath.Join(t.TempDir(), "missing")}
+	if err := cache.SetMaxBytes(1024); err != nil {
+		t.Fatalf("SetMaxBytes before the first download: %v", err)
+	}
+}
+
+func TestThumbnailCacheConcurrentFetch(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte("jpeg"))
+	}))
+	t.Cleanup(server.Close)
+	cache := &ThumbnailCache{Dir: t.TempDir()}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- cache.Fetch(context.Background(), "dQw4w9WgXcQ", server.URL)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Fetch: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(cache.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Fatalf("temp file left behind: %s", entry.Name())
+		}
+	}
+	if cache.LocalURL("dQw4w9WgXcQ") != ThumbnailRoutePrefix+"dQw4w9WgXcQ.jpg" {
+		t.Fatal("thumbnail not cached")
+	}
+}
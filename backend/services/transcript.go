@@ -2,38 +2,327 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 
+	yt_transcript_errors "github.com/horiagug/youtube-transcript-api-go/pkg/errors"
 	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript"
-	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_formatters"
+	"github.com/horiagug/youtube-transcript-api-go/pkg/yt_transcript_models"
 )
 
-type TranscriptService struct{}
+// TranscriptService fetches caption tracks through Providers, trying each in
+// order until one returns a transcript, and, when a video has none, runs the
+// local speech-to-text pipeline in asr.go.
+type TranscriptService struct {
+	Providers []TranscriptProvider
 
-func (s *TranscriptService) FetchTranscript(ctx context.Context, videoID string, languages []string) (string, error) {
-	_ = ctx
+	asrMu    sync.Mutex
+	asrSlots chan struct{}
+}
 
-	id := extractVideoIDFromInput(videoID)
-	if id == "" {
-		return "", fmt.Errorf("videoID is required")
+// TranscriptProvider is one way of getting a video's captions.
+type TranscriptProvider interface {
+	Name() string
+	FetchTranscript(ctx context.Context, videoID string, languages []string, track string) (Transcript, error)
+}
+
+// ErrProviderDisabled is returned by providers that are switched off in the
+// settings; the chain skips them without recording an error.
+var ErrProviderDisabled = errors.New("transcript provider disabled")
+
+// ErrNoTranscript marks a video that has no caption track matching the
+// request, as opposed to a fetch that failed on the way.
+var ErrNoTranscript = errors.New("no transcript found")
+
+// noTranscript keeps the provider's own message while matching
+// ErrNoTranscript.
+type noTranscript struct{ msg string }
+
+func (e noTranscript) Error() string        { return e.msg }
+func (e noTranscript) Is(target error) bool { return target == ErrNoTranscript }
+
+// ErrRateLimited marks a fetch that YouTube refused with HTTP 429.
+var ErrRateLimited = errors.New("rate limited")
+
+// rateLimited keeps the provider's own message while matching
+// ErrRateLimited.
+type rateLimited struct{ msg string }
+
+func (e rateLimited) Error() string        { return e.msg }
+func (e rateLimited) Is(target error) bool { return target == ErrRateLimited }
+
+// markRateLimit returns err as a rateLimited error when it reports an HTTP
+// 429. The caption client and yt-dlp only return plain strings, so the
+// markers include the status wording to avoid matching a stray "429" in a
+// video ID.
+func markRateLimit(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, yt_transcript_errors.ErrTooManyRequests) {
+		return rateLimited{msg: err.Error()}
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"status code: 429", "http error 429", "too many requests"} {
+		if strings.Contains(msg, marker) {
+			return rateLimited{msg: err.Error()}
+		}
 	}
+	return err
+}
+
+// IsNoTranscript reports whether err says the video has no matching
+// captions. Every provider in a TranscriptChainError must say so; one that
+// merely failed, on the network or after a YouTube change, gives no answer.
+func IsNoTranscript(err error) bool {
+	var chain TranscriptChainError
+	if errors.As(err, &chain) {
+		if len(chain) == 0 {
+			return false
+		}
+		for _, pe := range chain {
+			if !errors.Is(pe.Err, ErrNoTranscript) {
+				return false
+			}
+		}
+		return true
+	}
+	return errors.Is(err, ErrNoTranscript)
+}
+
+// TranscriptChainError carries the error of every provider that was tried.
+type TranscriptChainError []TranscriptProviderError
+
+type TranscriptProviderError struct {
+	Provider string
+	Err      error
+}
+
+func (e TranscriptChainError) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, pe := range e {
+		parts = append(parts, pe.Provider+": "+pe.Err.Error())
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e TranscriptChainError) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, pe := range e {
+		errs = append(errs, pe.Err)
+	}
+	return errs
+}
+
+// Where a stored transcript came from.
+const (
+	TranscriptSourceCaptions = "captions"
+	TranscriptSourceYTDLP    = "ytdlp"
+	TranscriptSourceASR      = "asr"
+)
+
+// Caption track choices. TranscriptTrackPreferManual falls back to
+// auto-generated captions when no manual track matches a language.
+const (
+	TranscriptTrackPreferManual = "prefer_manual"
+	TranscriptTrackManual       = "manual"
+	TranscriptTrackGenerated    = "generated"
+)
+
+func IsValidTranscriptTrack(track string) bool {
+	switch track {
+	case TranscriptTrackPreferManual, TranscriptTrackManual, TranscriptTrackGenerated:
+		return true
+	}
+	return false
+}
+
+// Transcript is one caption track with its timed segments. Text joins the
+// segments for prompts that do not need timing.
+type Transcript struct {
+	Language string
+	// Track is TranscriptTrackManual or TranscriptTrackGenerated for
+	// captions and empty for ASR output.
+	Track    string
+	Source   string
+	Text     string
+	Segments []TranscriptSegment
+}
+
+type TranscriptSegment struct {
+	Start    float64
+	Duration float64
+	Text     string
+}
+
+// FetchTranscript returns the caption track matching the earliest entry in
+// languages that satisfies track, from the first provider that has one. When
+// every provider fails the error is a TranscriptChainError.
+func (s *TranscriptService) FetchTranscript(ctx context.Context, videoID string, languages []string, track string) (Transcript, error) {
 	if len(languages) == 0 {
 		languages = []string{"en"}
 	}
+	providers := s.Providers
+	if len(providers) == 0 {
+		providers = []TranscriptProvider{&CaptionAPIProvider{}}
+	}
 
-	formatter := yt_transcript_formatters.NewTextFormatter(
-		yt_transcript_formatters.WithTimestamps(false),
-	)
-	client := yt_transcript.NewClient(
-		yt_transcript.WithFormatter(formatter),
-	)
+	var chain TranscriptChainError
+	for _, provider := range providers {
+		transcript, err := provider.FetchTranscript(ctx, videoID, languages, track)
+		if err == nil {
+			return transcript, nil
+		}
+		if errors.Is(err, ErrProviderDisabled) {
+			continue
+		}
+		chain = append(chain, TranscriptProviderError{Provider: provider.Name(), Err: err})
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(chain) == 0 {
+		return Transcript{}, fmt.Errorf("no transcript provider is enabled")
+	}
+	return Transcript{}, chain
+}
 
-	transcript, err := client.GetFormattedTranscripts(id, languages, true)
+// CaptionAPIProvider reads caption tracks through the player API used by
+// youtube-transcript-api-go.
+type CaptionAPIProvider struct{}
+
+func (p *CaptionAPIProvider) Name() string {
+	return "caption api"
+}
+
+func (p *CaptionAPIProvider) FetchTranscript(ctx context.Context, videoID string, languages []string, track string) (Transcript, error) {
+	_ = ctx
+
+	id := extractVideoIDFromInput(videoID)
+	if id == "" {
+		return Transcript{}, fmt.Errorf("videoID is required")
+	}
+
+	client := yt_transcript.NewClient()
+	tracks, err := client.GetTranscripts(id, languages)
 	if err != nil {
-		return "", err
+		if isMissingCaptionsError(err) {
+			return Transcript{}, noTranscript{msg: err.Error()}
+		}
+		return Transcript{}, markRateLimit(err)
+	}
+	picked, ok := pickTranscript(tracks, languages, track)
+	if !ok {
+		return Transcript{}, noTranscriptError(languages, track)
+	}
+
+	kind := TranscriptTrackManual
+	if picked.IsGenerated {
+		kind = TranscriptTrackGenerated
+	}
+	segments := make([]TranscriptSegment, 0, len(picked.Lines))
+	for _, line := range picked.Lines {
+		segments = append(segments, TranscriptSegment{
+			Start:    line.Start,
+			Duration: line.Duration,
+			Text:     line.Text,
+		})
+	}
+	return newTranscript(picked.LanguageCode, kind, TranscriptSourceCaptions, segments), nil
+}
+
+// newTranscript drops empty segments and joins the rest into Text.
+func newTranscript(language string, track string, source string, segments []TranscriptSegment) Transcript {
+	transcript := Transcript{
+		Language: language,
+		Track:    track,
+		Source:   source,
+		Segments: make([]TranscriptSegment, 0, len(segments)),
+	}
+	lines := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		seg.Text = strings.TrimSpace(seg.Text)
+		if seg.Text == "" {
+			continue
+		}
+		transcript.Segments = append(transcript.Segments, seg)
+		lines = append(lines, seg.Text)
+	}
+	transcript.Text = strings.Join(lines, "\n")
+	return transcript
+}
+
+func noTranscriptError(languages []string, track string) error {
+	switch track {
+	case TranscriptTrackManual:
+		return noTranscript{msg: fmt.Sprintf("no manual transcript found for languages %v", languages)}
+	case TranscriptTrackGenerated:
+		return noTranscript{msg: fmt.Sprintf("no auto-generated transcript found for languages %v", languages)}
+	}
+	return noTranscript{msg: fmt.Sprintf("no transcript found for languages %v", languages)}
+}
+
+// isMissingCaptionsError recognizes the caption client's messages for a
+// video with captions disabled or none in the requested languages. The
+// client only returns plain strings.
+func isMissingCaptionsError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"captions not found", "playercaptionstracklistrenderer not found", "no transcript found", "no transcripts found"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// TimestampURL deep-links a YouTube watch URL to an offset in seconds.
+func TimestampURL(videoURL string, seconds float64) string {
+	u, err := url.Parse(strings.TrimSpace(videoURL))
+	if err != nil || u.Host == "" {
+		return videoURL
+	}
+	q := u.Query()
+	q.Set("t", strconv.Itoa(int(seconds))+"s")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// pickTranscript ranks tracks by the position of their language in languages
+// and, within a language, manual captions first. Tracks of the wrong kind are
+// skipped when kind asks for one kind only. The client returns tracks in no
+// particular order.
+func pickTranscript(tracks []yt_transcript_models.Transcript, languages []string, kind string) (yt_transcript_models.Transcript, bool) {
+	best := -1
+	bestRank := 0
+	for i, track := range tracks {
+		if len(track.Lines) == 0 {
+			continue
+		}
+		if (kind == TranscriptTrackManual && track.IsGenerated) || (kind == TranscriptTrackGenerated && !track.IsGenerated) {
+			continue
+		}
+		rank := len(languages) * 2
+		for j, lang := range languages {
+			if strings.EqualFold(track.LanguageCode, lang) {
+				rank = j * 2
+				break
+			}
+		}
+		if track.IsGenerated {
+			rank++
+		}
+		if best == -1 || rank < bestRank {
+			best, bestRank = i, rank
+		}
+	}
+	if best == -1 {
+		return yt_transcript_models.Transcript{}, false
 	}
-	return strings.TrimSpace(transcript), nil
+	return tracks[best], true
 }
 
 func extractVideoIDFromInput(input string) string {
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsNoTranscript(t *testing.T) {
+	missing := noTranscriptError([]string{"en"}, TranscriptTrackManual)
+	failed := errors.New("failed to fetch video page: EOF")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"provider without captions", missing, true},
+		{"wrapped", fmt.Errorf("fetch: %w", missing), true},
+		{"network failure", failed, false},
+		{"every provider without captions", TranscriptChainError{
+			{Provider: "caption api", Err: missing},
+			{Provider: "yt-dlp subtitles", Err: missing},
+		}, true},
+		{"one provider failed", TranscriptChainError{
+			{Provider: "caption api", Err: failed},
+			{Provider: "yt-dlp subtitles", Err: missing},
+		}, false},
+		{"empty chain", TranscriptChainError{}, false},
+	}
+	for _, tt := range tests {
+		if got := IsNoTranscript(tt.err); got != tt.want {
+			t.Errorf("%s: IsNoTranscript = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsMissingCaptionsError(t *testing.T) {
+	for _, msg := range []string{
+		"failed to extract list of transcripts: captions not found in response",
+		"playerCaptionsTracklistRenderer not found",
+		"failed to get transcript: no transcript found for languages [en]",
+	} {
+		if !isMissingCaptionsError(errors.New(msg)) {
+			t.Errorf("%q not recognized as missing captions", msg)
+		}
+	}
+	if isMissingCaptionsError(errors.New("received non-OK status code: 429")) {
+		t.Error("rate limit recognized as missing captions")
+	}
+}
+
+func TestMarkRateLimit(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want bool
+	}{
+		{"failed to fetch transcript: received non-OK status code: 429", true},
+		{"yt-dlp failed: exit status 1: ERROR: [youtube] dQw4w9WgXcQ: Unable to download video subtitles: HTTP Error 429: Too Many Requests", true},
+		{"yt-dlp failed: exit status 1: ERROR: [youtube] x429abcdefg: Video unavailable", false},
+		{"dial tcp 127.0.0.1:4290: connection refused", false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(markRateLimit(errors.New(tt.msg)), ErrRateLimited); got != tt.want {
+			t.Errorf("markRateLimit(%q) rate limited = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultWebSubHubURL = "https://pubsubhubbub.appspot.com/subscribe"
+
+// WebSubService talks to a WebSub (PubSubHubbub) hub on behalf of the
+// callback server run by the app.
+type WebSubService struct {
+	Client *http.Client
+}
+
+type WebSubRequest struct {
+	HubURL       string
+	Topic        string
+	Callback     string
+	Secret       string
+	LeaseSeconds int
+}
+
+type WebSubNotification struct {
+	ChannelID       string
+	Entries         []FeedEntry
+	DeletedVideoIDs []string
+}
+
+// ChannelTopicURL is the topic YouTube publishes channel uploads under.
+func ChannelTopicURL(channelID string) string {
+	return "https://www.youtube.com/xml/feeds/videos.xml?channel_id=" + url.QueryEscape(channelID)
+}
+
+func (s *WebSubService) Subscribe(ctx context.Context, req WebSubRequest) error {
+	return s.send(ctx, "subscribe", req)
+}
+
+func (s *WebSubService) Unsubscribe(ctx context.Context, req WebSubRequest) error {
+	return s.send(ctx, "unsubscribe", req)
+}
+
+func (s *WebSubService) send(ctx context.Context, mode string, req WebSubRequest) error {
+	if strings.TrimSpace(req.HubURL) == "" {
+		return fmt.Errorf("hub url is required")
+	}
+	if strings.TrimSpace(req.Topic) == "" || strings.TrimSpace(req.Callback) == "" {
+		return fmt.Errorf("topic and callback are required")
+	}
+
+	form := url.Values{}
+	form.Set("hub.mode", mode)
+	form.Set("hub.topic", req.Topic)
+	form.Set("hub.callback", req.Callback)
+	form.Set("hub.verify", "async")
+	if req.Secret != "" {
+		form.Set("hub.secret", req.Secret)
+	}
+	if req.LeaseSeconds > 0 {
+		form.Set("hub.lease_seconds", strconv.Itoa(req.LeaseSeconds))
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.HubURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("hub %s failed: status %d: %s", mode, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// VerifySignature checks the X-Hub-Signature header ("sha1=<hex>" or
+// "sha256=<hex>") of a pushed body against the subscription secret.
+func (s *WebSubService) VerifySignature(secret string, body []byte, header string) bool {
+	algo, sig, ok := strings.Cut(strings.TrimSpace(header), "=")
+	if !ok {
+		return false
+	}
+	var newHash func() hash.Hash
+	switch strings.ToLower(algo) {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	default:
+		return false
+	}
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// ParseNotification reads a pushed Atom document. YouTube sends new and
+// updated videos as entries and deletions as tombstones.
+func (s *WebSubService) ParseNotification(body []byte) (*WebSubNotification, error) {
+	var raw webSubFeed
+	if err := xml.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	notification := &WebSubNotification{
+		Entries: convertEntries(raw.Entries),
+	}
+	for _, entry := range notification.Entries {
+		if entry.ChannelID != "" {
+			notification.ChannelID = entry.ChannelID
+			break
+		}
+	}
+	for _, link := range raw.Links {
+		if notification.ChannelID != "" {
+			break
+		}
+		notification.ChannelID = ChannelIDFromFeedURL(link.Href)
+	}
+	for _, deleted := range raw.Deleted {
+		if id := extractVideoID(strings.TrimSpace(deleted.Ref)); id != "" {
+			notification.DeletedVideoIDs = append(notification.DeletedVideoIDs, id)
+		}
+		if notification.ChannelID == "" {
+			notification.ChannelID = ChannelIDFromFeedURL(strings.TrimSpace(deleted.By.URI))
+		}
+	}
+	return notification, nil
+}
+
+type webSubFeed struct {
+	Links   []ytLink         `xml:"link"`
+	Entries []ytEntry        `xml:"entry"`
+	Deleted []webSubDeletion `xml:"http://purl.org/atompub/tombstones/1.0 deleted-entry"`
+}
+
+type webSubDeletion struct {
+	Ref string `xml:"ref,attr"`
+	// By names the channel as https://www.youtube.com/channel/UC...
+	By struct {
+		URI string `xml:"uri"`
+	} `xml:"http://purl.org/atompub/tombstones/1.0 by"`
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWebSubSubscribe(t *testing.T) {
+	var form url.Values
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		form = r.PostForm
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer hub.Close()
+
+	service := &WebSubService{}
+	err := service.Subscribe(context.Background(), WebSubRequest{
+		HubURL:       hub.URL,
+		Topic:        ChannelTopicURL("UCabcdefghijklmnopqrstuv"),
+		Callback:     "https://example.com/websub",
+		Secret:       "s3cret",
+		LeaseSeconds: 3600,
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	want := url.Values{
+		"hub.mode":          {"subscribe"},
+		"hub.topic":         {"https://www.youtube.com/xml/feeds/videos.xml?channel_id=UCabcdefghijklmnopqrstuv"},
+		"hub.callback":      {"https://example.com/websub"},
+		"hub.verify":        {"async"},
+		"hub.secret":        {"s3cret"},
+		"hub.lease_seconds": {"3600"},
+	}
+	if !reflect.DeepEqual(form, want) {
+		t.Fatalf("hub received %v, want %v", form, want)
+	}
+}
+
+func TestWebSubSubscribeRejected(t *testing.T) {
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad callback", http.StatusBadRequest)
+	}))
+	defer hub.Close()
+
+	err := (&WebSubService{}).Unsubscribe(context.Background(), WebSubRequest{
+		HubURL:   hub.URL,
+		Topic:    ChannelTopicURL("UCabcdefghijklmnopqrstuv"),
+		Callback: "https://example.com/websub",
+	})
+	if err == nil || !strings.Contains(err.Error(), "status 400") || !strings.Contains(err.Error(), "bad callback") {
+		t.Fatalf("err = %v, want the hub's status and message", err)
+	}
+}
+
+func TestWebSubVerifySignature(t *testing.T) {
+	body := []byte("<feed/>")
+	sign := func(secret string, sha256Hash bool) string {
+		if sha256Hash {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		}
+		mac := hmac.New(sha1.New, []byte(secret))
+		mac.Write(body)
+		return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	service := &WebSubService{}
+	tests := []struct {
+		name   string
+		body   []byte
+		header string
+		want   bool
+	}{
+		{"sha1", body, sign("secret", false), true},
+		{"sha256", body, sign("secret", true), true},
+		{"wrong secret", body, sign("other", false), false},
+		{"tampered body", []byte("<feed></feed>"), sign("secret", false), false},
+		{"missing header", body, "", false},
+		{"unknown algorithm", body, "md5=abcd", false},
+		{"not hex", body, "sha1=zz", false},
+	}
+	for _, tt := range tests {
+		if got := service.VerifySignature("secret", tt.body, tt.header); got != tt.want {
+			t.Errorf("%s: VerifySignature = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWebSubParseTombstone(t *testing.T) {
+	body := `<feed xmlns:at="http://purl.org/atompub/tombstones/1.0" xmlns="http://www.w3.org/2005/Atom">
+  <at:deleted-entry ref="yt:video:dQw4w9WgXcQ" when="2026-10-02T00:00:00+00:00">
+    <link href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+    <at:by>
+      <name>Test</name>
+      <uri>https://www.youtube.com/channel/UCabcdefghijklmnopqrstuv</uri>
+    </at:by>
+  </at:deleted-entry>
+</feed>`
+	notification, err := (&WebSubService{}).ParseNotification([]byte(body))
+	if err != nil {
+		t.Fatalf("ParseNotification: %v", err)
+	}
+	if notification.ChannelID != "UCabcdefghijklmnopqrstuv" {
+		t.Fatalf("channel = %q, want the tombstone's author", notification.ChannelID)
+	}
+	if !reflect.DeepEqual(notification.DeletedVideoIDs, []string{"dQw4w9WgXcQ"}) {
+		t.Fatalf("deleted = %v", notification.DeletedVideoIDs)
+	}
+}
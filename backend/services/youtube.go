@@ -5,89 +5,161 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
 
 type YouTubeService struct {
-	Client *http.Client
+	Client  *http.Client
+	BaseURL string
 }
 
-type YouTubeFeed struct {
-	ChannelID   string
-	ChannelName string
-	ChannelURL  string
-	Entries     []YouTubeEntry
+type YouTubePlaylistFeed struct {
+	PlaylistID string
+	Title      string
+	Author     string
+	URL        string
+	Entries    []FeedEntry
 }
 
-type YouTubeEntry struct {
-	VideoID     string
-	Title       string
-	URL         string
-	Thumbnail   string
-	PublishedAt time.Time
-	UpdatedAt   time.Time
+func (s *YouTubeService) Type() string {
+	return SourceYouTube
 }
 
-func (s *YouTubeService) FetchChannelFeed(ctx context.Context, channelID string) (*YouTubeFeed, error) {
+func (s *YouTubeService) Resolve(ctx context.Context, input string) (string, error) {
+	return s.ResolveChannelID(ctx, input)
+}
+
+func (s *YouTubeService) FetchFeed(ctx context.Context, channelID string, cache FeedCache) (*Feed, error) {
+	return s.FetchChannelFeed(ctx, channelID, cache)
+}
+
+func (s *YouTubeService) FetchChannelFeed(ctx context.Context, channelID string, cache FeedCache) (*Feed, error) {
 	if channelID == "" {
 		return nil, fmt.Errorf("channelID is required")
 	}
 
+	raw, next, err := s.fetchFeed(ctx, feedURL(s.baseURL(), channelID), cache)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return &Feed{
+			ChannelID:   channelID,
+			NotModified: true,
+			Cache:       next,
+		}, nil
+	}
+
+	channelID = extractChannelID(raw.ID, channelID)
+	channelURL := fmt.Sprintf("https://www.youtube.com/channel/%s", channelID)
+
+	return &Feed{
+		ChannelID:   channelID,
+		ChannelName: strings.TrimSpace(raw.Author.Name),
+		ChannelURL:  channelURL,
+		Entries:     convertEntries(raw.Entries),
+		Cache:       next,
+	}, nil
+}
+
+func (s *YouTubeService) FetchPlaylistFeed(ctx context.Context, playlistID string) (*YouTubePlaylistFeed, error) {
+	if playlistID == "" {
+		return nil, fmt.Errorf("playlistID is required")
+	}
+
+	raw, _, err := s.fetchFeed(ctx, playlistFeedURL(s.baseURL(), playlistID), FeedCache{})
+	if err != nil {
+		return nil, err
+	}
+
+	if id := strings.TrimSpace(raw.PlaylistID); id != "" {
+		playlistID = id
+	}
+
+	return &YouTubePlaylistFeed{
+		PlaylistID: playlistID,
+		Title:      strings.TrimSpace(raw.Title),
+		Author:     strings.TrimSpace(raw.Author.Name),
+		URL:        fmt.Sprintf("https://www.youtube.com/playlist?list=%s", playlistID),
+		Entries:    convertEntries(raw.Entries),
+	}, nil
+}
+
+// fetchFeed returns a nil feed when the server answers 304 Not Modified for
+// the validators in cache.
+func (s *YouTubeService) fetchFeed(ctx context.Context, endpoint string, cache FeedCache) (*ytFeed, FeedCache, error) {
 	client := s.Client
 	if client == nil {
 		client = &http.Client{Timeout: 15 * time.Second}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL(channelID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
-		return nil, err
+		return nil, cache, err
+	}
+	if cache.ETag != "" {
+		req.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cache.LastModified)
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, cache, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, cache, nil
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("feed request failed: status %d", resp.StatusCode)
+		return nil, cache, fmt.Errorf("feed request failed: status %d", resp.StatusCode)
 	}
 
 	var raw ytFeed
 	if err := xml.NewDecoder(resp.Body).Decode(&raw); err != nil {
-		return nil, err
+		return nil, cache, err
 	}
-
-	channelID = extractChannelID(raw.ID, channelID)
-	channelURL := fmt.Sprintf("https://www.youtube.com/channel/%s", channelID)
-
-	feed := &YouTubeFeed{
-		ChannelID:   channelID,
-		ChannelName: strings.TrimSpace(raw.Author.Name),
-		ChannelURL:  channelURL,
-		Entries:     make([]YouTubeEntry, 0, len(raw.Entries)),
+	next := FeedCache{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
 	}
+	return &raw, next, nil
+}
 
-	for _, entry := range raw.Entries {
+func convertEntries(raw []ytEntry) []FeedEntry {
+	entries := make([]FeedEntry, 0, len(raw))
+	for _, entry := range raw {
 		videoID := extractVideoID(entry.ID)
 		publishedAt, _ := time.Parse(time.RFC3339, entry.Published)
 		updatedAt, _ := time.Parse(time.RFC3339, entry.Updated)
-		feed.Entries = append(feed.Entries, YouTubeEntry{
+		entries = append(entries, FeedEntry{
 			VideoID:     videoID,
+			ChannelID:   strings.TrimSpace(entry.ChannelID),
 			Title:       strings.TrimSpace(entry.Title),
 			URL:         entry.Link.Href,
 			Thumbnail:   entry.MediaGroup.Thumbnail.URL,
+			Description: strings.TrimSpace(entry.MediaGroup.Description),
+			Views:       entry.MediaGroup.Community.Statistics.Views,
+			RatingCount: entry.MediaGroup.Community.StarRating.Count,
+			Rating:      entry.MediaGroup.Community.StarRating.Average,
+			VideoType:   classifyByLink(entry.Link.Href),
 			PublishedAt: publishedAt,
 			UpdatedAt:   updatedAt,
 		})
 	}
+	return entries
+}
 
-	return feed, nil
+func feedURL(baseURL, channelID string) string {
+	return fmt.Sprintf("%s/feeds/videos.xml?channel_id=%s", baseURL, channelID)
 }
 
-func feedURL(channelID string) string {
-	return fmt.Sprintf("https://www.youtube.com/feeds/videos.xml?channel_id=%s", channelID)
+func playlistFeedURL(baseURL, playlistID string) string {
+	return fmt.Sprintf("%s/feeds/videos.xml?playlist_id=%s", baseURL, playlistID)
 }
 
 func extractChannelID(id, fallback string) string {
@@ -105,10 +177,11 @@ func extractVideoID(id string) string {
 }
 
 type ytFeed struct {
-	ID      string    `xml:"id"`
-	Title   string    `xml:"title"`
-	Author  ytAuthor  `xml:"author"`
-	Entries []ytEntry `xml:"entry"`
+	ID         string    `xml:"id"`
+	PlaylistID string    `xml:"http://www.youtube.com/xml/schemas/2015 playlistId"`
+	Title      string    `xml:"title"`
+	Author     ytAuthor  `xml:"author"`
+	Entries    []ytEntry `xml:"entry"`
 }
 
 type ytAuthor struct {
@@ -118,11 +191,12 @@ type ytAuthor struct {
 
 type ytEntry struct {
 	ID         string       `xml:"id"`
+	ChannelID  string       `xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
 	Title      string       `xml:"title"`
 	Link       ytLink       `xml:"link"`
 	Published  string       `xml:"published"`
 	Updated    string       `xml:"updated"`
-	MediaGroup ytMediaGroup `xml:"media:group"`
+	MediaGroup ytMediaGroup `xml:"http://search.yahoo.com/mrss/ group"`
 }
 
 type ytLink struct {
@@ -130,9 +204,62 @@ type ytLink struct {
 }
 
 type ytMediaGroup struct {
-	Thumbnail ytThumbnail `xml:"media:thumbnail"`
+	Thumbnail   ytThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Description string      `xml:"http://search.yahoo.com/mrss/ description"`
+	Community   ytCommunity `xml:"http://search.yahoo.com/mrss/ community"`
+}
+
+type ytCommunity struct {
+	StarRating ytStarRating `xml:"http://search.yahoo.com/mrss/ starRating"`
+	Statistics ytStatistics `xml:"http://search.yahoo.com/mrss/ statistics"`
+}
+
+type ytStarRating struct {
+	Count   int64   `xml:"count,attr"`
+	Average float64 `xml:"average,attr"`
+}
+
+type ytStatistics struct {
+	Views int64 `xml:"views,attr"`
 }
 
 type ytThumbnail struct {
 	URL string `xml:"url,attr"`
 }
+
+func (s *YouTubeService) CheckVideoAvailable(ctx context.Context, videoID string) (bool, error) {
+	if videoID == "" {
+		return false, fmt.Errorf("videoID is required")
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	watchURL := "https://www.youtube.com/watch?v=" + videoID
+	endpoint := fmt.Sprintf("%s/oembed?format=json&url=%s", s.baseURL(), url.QueryEscape(watchURL))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return true, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		// Private videos answer 401. Embedding-disabled videos do too, but
+		// those stay in the channel feed and so are never checked.
+		return false, nil
+	default:
+		return false, fmt.Errorf("oembed request failed: status %d", resp.StatusCode)
+	}
+}
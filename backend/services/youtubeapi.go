@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultYouTubeAPIBaseURL = "https://www.googleapis.com/youtube/v3"
+
+type YouTubeDataService struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+type UploadsPage struct {
+	Entries       []FeedEntry
+	NextPageToken string
+}
+
+func (s *YouTubeDataService) UploadsPlaylistID(ctx context.Context, apiKey string, channelID string) (string, error) {
+	if channelID == "" {
+		return "", fmt.Errorf("channelID is required")
+	}
+	params := url.Values{}
+	params.Set("part", "contentDetails")
+	params.Set("id", channelID)
+
+	var out struct {
+		Items []struct {
+			ContentDetails struct {
+				RelatedPlaylists struct {
+					Uploads string `json:"uploads"`
+				} `json:"relatedPlaylists"`
+			} `json:"contentDetails"`
+		} `json:"items"`
+	}
+	if err := s.get(ctx, apiKey, "/channels", params, &out); err != nil {
+		return "", err
+	}
+	if len(out.Items) == 0 || out.Items[0].ContentDetails.RelatedPlaylists.Uploads == "" {
+		return "", fmt.Errorf("uploads playlist not found for channel %s", channelID)
+	}
+	return out.Items[0].ContentDetails.RelatedPlaylists.Uploads, nil
+}
+
+func (s *YouTubeDataService) ListPlaylistItems(ctx context.Context, apiKey string, playlistID string, pageToken string) (*UploadsPage, error) {
+	if playlistID == "" {
+		return nil, fmt.Errorf("playlistID is required")
+	}
+	params := url.Values{}
+	params.Set("part", "snippet,contentDetails")
+	params.Set("playlistId", playlistID)
+	params.Set("maxResults", "50")
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	var out playlistItemsResponse
+	if err := s.get(ctx, apiKey, "/playlistItems", params, &out); err != nil {
+		return nil, err
+	}
+
+	page := &UploadsPage{
+		Entries:       make([]FeedEntry, 0, len(out.Items)),
+		NextPageToken: out.NextPageToken,
+	}
+	for _, item := range out.Items {
+		videoID := item.ContentDetails.VideoID
+		if videoID == "" {
+			continue
+		}
+		published := item.ContentDetails.VideoPublishedAt
+		if published == "" {
+			published = item.Snippet.PublishedAt
+		}
+		publishedAt, _ := time.Parse(time.RFC3339, published)
+		page.Entries = append(page.Entries, FeedEntry{
+			VideoID:     videoID,
+			ChannelID:   item.Snippet.VideoOwnerChannelID,
+			Title:       strings.TrimSpace(item.Snippet.Title),
+			URL:         "https://www.youtube.com/watch?v=" + videoID,
+			Thumbnail:   item.Snippet.Thumbnails.best(),
+			Description: strings.TrimSpace(item.Snippet.Description),
+			PublishedAt: publishedAt,
+		})
+	}
+	return page, nil
+}
+
+func (s *YouTubeDataService) get(ctx context.Context, apiKey string, path string, params url.Values, out interface{}) error {
+	if s == nil {
+		return fmt.Errorf("youtube data api is not configured")
+	}
+	if strings.TrimSpace(apiKey) == "" {
+		return fmt.Errorf("youtube api key is required")
+	}
+	baseURL := strings.TrimRight(s.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultYouTubeAPIBaseURL
+	}
+	params.Set("key", apiKey)
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("youtube api request failed: status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type playlistItemsResponse struct {
+	NextPageToken string `json:"nextPageToken"`
+	Items         []struct {
+		Snippet struct {
+			PublishedAt         string       `json:"publishedAt"`
+			Title               string       `json:"title"`
+			Description         string       `json:"description"`
+			VideoOwnerChannelID string       `json:"videoOwnerChannelId"`
+			Thumbnails          apiThumbnail `json:"thumbnails"`
+		} `json:"snippet"`
+		ContentDetails struct {
+			VideoID          string `json:"videoId"`
+			VideoPublishedAt string `json:"videoPublishedAt"`
+		} `json:"contentDetails"`
+	} `json:"items"`
+}
+
+type apiThumbnail struct {
+	Default struct {
+		URL string `json:"url"`
+	} `json:"default"`
+	Medium struct {
+		URL string `json:"url"`
+	} `json:"medium"`
+	High struct {
+		URL string `json:"url"`
+	} `json:"high"`
+}
+
+func (t apiThumbnail) best() string {
+	switch {
+	case t.High.URL != "":
+		return t.High.URL
+	case t.Medium.URL != "":
+		return t.Medium.URL
+	default:
+		return t.Default.URL
+	}
+}
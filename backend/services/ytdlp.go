@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultYTDLPBinary = "yt-dlp"
+
+// ytdlpTimeout bounds a single metadata or subtitle run so a stalled yt-dlp
+// cannot hold up a sync or a summary.
+const ytdlpTimeout = 2 * time.Minute
+
+// YTDLPService runs a local yt-dlp binary for metadata the feeds do not
+// carry. The binary path comes from settings; empty means "yt-dlp" on PATH.
+type YTDLPService struct{}
+
+type VideoMetadata struct {
+	DurationSeconds int
+	Description     string
+	Chapters        []VideoChapter
+	Category        string
+	Language        string
+}
+
+type VideoChapter struct {
+	Title        string
+	StartSeconds int
+	EndSeconds   int
+}
+
+func (s *YTDLPService) FetchMetadata(ctx context.Context, binary string, videoURL string) (VideoMetadata, error) {
+	if strings.TrimSpace(videoURL) == "" {
+		return VideoMetadata{}, fmt.Errorf("video url is required")
+	}
+	out, err := s.run(ctx, binary, "--dump-json", "--skip-download", "--no-playlist", "--no-warnings", videoURL)
+	if err != nil {
+		return VideoMetadata{}, err
+	}
+
+	var raw struct {
+		Duration    float64  `json:"duration"`
+		Description string   `json:"description"`
+		Categories  []string `json:"categories"`
+		Language    string   `json:"language"`
+		Chapters    []struct {
+			Title     string  `json:"title"`
+			StartTime float64 `json:"start_time"`
+			EndTime   float64 `json:"end_time"`
+		} `json:"chapters"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return VideoMetadata{}, fmt.Errorf("invalid yt-dlp output: %w", err)
+	}
+
+	meta := VideoMetadata{
+		DurationSeconds: int(raw.Duration),
+		Description:     strings.TrimSpace(raw.Description),
+		Language:        strings.TrimSpace(raw.Language),
+	}
+	if len(raw.Categories) > 0 {
+		meta.Category = strings.TrimSpace(raw.Categories[0])
+	}
+	for _, ch := range raw.Chapters {
+		meta.Chapters = append(meta.Chapters, VideoChapter{
+			Title:        strings.TrimSpace(ch.Title),
+			StartSeconds: int(ch.StartTime),
+			EndSeconds:   int(ch.EndTime),
+		})
+	}
+	return meta, nil
+}
+
+func (s *YTDLPService) run(ctx context.Context, binary string, args ...string) ([]byte, error) {
+	binary = strings.TrimSpace(binary)
+	if binary == "" {
+		binary = defaultYTDLPBinary
+	}
+	ctx, cancel := context.WithTimeout(ctx, ytdlpTimeout)
+	defer cancel()
+	return runTool(ctx, "yt-dlp", binary, args...)
+}
+
+// runTool runs a local binary without a console window and returns its
+// stdout. Failures carry the tail of stderr, labelled with name.
+func runTool(ctx context.Context, name string, binary string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, binary, args...)
+	hideConsole(cmd)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 300 {
+			msg = msg[len(msg)-300:]
+		}
+		if msg != "" {
+			return nil, fmt.Errorf("%s failed: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// FormatDuration renders seconds as "m:ss" or "h:mm:ss".
+func FormatDuration(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	h := seconds / 3600
+	m := (seconds % 3600) / 60
+	sec := seconds % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%d:%02d", m, sec)
+}
+
+// FormatChapters renders chapters one per line as "m:ss Title", which is
+// what the {key_points} template variable receives.
+func FormatChapters(chapters []VideoChapter) string {
+	lines := make([]string, 0, len(chapters))
+	for _, ch := range chapters {
+		start := FormatDuration(ch.StartSeconds)
+		if start == "" {
+			start = "0:00"
+		}
+		lines = append(lines, start+" "+ch.Title)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// YTDLPSubtitleProvider downloads WebVTT subtitles with yt-dlp. It keeps
+// working when the caption API client breaks after a YouTube change, at the
+// cost of a process start per video.
+type YTDLPSubtitleProvider struct {
+	mu      sync.Mutex
+	enabled bool
+	binary  string
+}
+
+// Configure switches the provider on or off and sets the yt-dlp binary;
+// empty means "yt-dlp" on PATH.
+func (p *YTDLPSubtitleProvider) Configure(enabled bool, binary string) {
+	p.mu.Lock()
+	p.enabled = enabled
+	p.binary = binary
+	p.mu.Unlock()
+}
+
+func (p *YTDLPSubtitleProvider) Name() string {
+	return "yt-dlp subtitles"
+}
+
+func (p *YTDLPSubtitleProvider) FetchTranscript(ctx context.Context, videoID string, languages []string, track string) (Transcript, error) {
+	p.mu.Lock()
+	enabled, binary := p.enabled, strings.TrimSpace(p.binary)
+	p.mu.Unlock()
+	if !enabled {
+		return Transcript{}, ErrProviderDisabled
+	}
+	if binary == "" {
+		binary = defaultYTDLPBinary
+	}
+
+	id := extractVideoIDFromInput(videoID)
+	if id == "" {
+		return Transcript{}, fmt.Errorf("videoID is required")
+	}
+	videoURL := "https://www.youtube.com/watch?v=" + id
+
+	// yt-dlp names manual and auto-generated files alike, so each kind gets
+	// its own run.
+	kinds := []string{TranscriptTrackManual, TranscriptTrackGenerated}
+	switch track {
+	case TranscriptTrackManual:
+		kinds = kinds[:1]
+	case TranscriptTrackGenerated:
+		kinds = kinds[1:]
+	}
+
+	for _, kind := range kinds {
+		transcript, ok, err := p.download(ctx, binary, videoURL, languages, kind)
+		if err != nil {
+			return Transcript{}, err
+		}
+		if ok {
+			return transcript, nil
+		}
+	}
+	return Transcript{}, noTranscriptError(languages, track)
+}
+
+func (p *YTDLPSubtitleProvider) download(ctx context.Context, binary string, videoURL string, languages []string, kind string) (Transcript, bool, error) {
+	dir, err := os.MkdirTemp("", "ytfeed-subs-")
+	if err != nil {
+		return Transcript{}, false, err
+	}
+	defer os.RemoveAll(dir)
+
+	writeFlag := "--write-subs"
+	if kind == TranscriptTrackGenerated {
+		writeFlag = "--write-auto-subs"
+	}
+	ctx, cancel := context.WithTimeout(ctx, ytdlpTimeout)
+	defer cancel()
+	if _, err := runTool(ctx, "yt-dlp", binary,
+		"--skip-download",
+		writeFlag,
+		"--sub-langs", strings.Join(languages, ","),
+		"--sub-format", "vtt",
+		"--no-playlist",
+		"--no-warnings",
+		"-o", filepath.Join(dir, "subs.%(ext)s"),
+		videoURL,
+	); err != nil {
+		return Transcript{}, false, markRateLimit(err)
+	}
+
+	for _, lang := range languages {
+		raw, err := os.ReadFile(filepath.Join(dir, "subs."+lang+".vtt"))
+		if err != nil {
+			continue
+		}
+		transcript := newTranscript(lang, kind, TranscriptSourceYTDLP, parseVTT(string(raw)))
+		if len(transcript.Segments) > 0 {
+			return transcript, true, nil
+		}
+	}
+	return Transcript{}, false, nil
+}
+
+var (
+	vttTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	vttTimingPattern = regexp.MustCompile(`^((?:\d+:)?\d{2}:\d{2}\.\d{3})\s+-->\s+((?:\d+:)?\d{2}:\d{2}\.\d{3})`)
+)
+
+// parseVTT reads the cues of a WebVTT file. Auto-generated YouTube subtitles
+// repeat the previous line at the top of every cue to make it scroll; those
+// repeats are dropped so each line appears once.
+func parseVTT(raw string) []TranscriptSegment {
+	var segments []TranscriptSegment
+	var last string
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var start, end float64
+	inCue := false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if m := vttTimingPattern.FindStringSubmatch(line); m != nil {
+			start, end = parseVTTTime(m[1]), parseVTTTime(m[2])
+			inCue = true
+			continue
+		}
+		if line == "" {
+			inCue = false
+			continue
+		}
+		if !inCue {
+			// Header, NOTE and STYLE blocks, and cue identifiers.
+			continue
+		}
+		text := strings.TrimSpace(html.UnescapeString(vttTagPattern.ReplaceAllString(line, "")))
+		if text == "" || text == last {
+			continue
+		}
+		last = text
+		segments = append(segments, TranscriptSegment{Start: start, Duration: end - start, Text: text})
+	}
+	return segments
+}
+
+// parseVTTTime parses "hh:mm:ss.mmm" or "mm:ss.mmm" into seconds.
+func parseVTTTime(value string) float64 {
+	parts := strings.Split(value, ":")
+	var seconds float64
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// autoCaptionsVTT is shaped like YouTube's auto-generated WebVTT: every cue
+// repeats the previous line to scroll it, and words carry inline <c>
+// timestamp tags.
+const autoCaptionsVTT = `WEBVTT
+Kind: captions
+Language: en
+
+00:00:00.000 --> 00:00:02.500 align:start position:0%
+ 
+hello<00:00:00.500><c> world</c>
+
+00:00:02.500 --> 00:00:02.510 align:start position:0%
+hello world
+ 
+
+00:00:02.510 --> 00:00:05.000 align:start position:0%
+hello world
+rock<00:00:03.000><c> &amp;</c><00:00:03.500><c> roll</c>
+
+00:00:05.000 --> 00:00:05.010 align:start position:0%
+rock &amp; roll
+ 
+`
+
+func TestParseVTT(t *testing.T) {
+	got := parseVTT(autoCaptionsVTT)
+	want := []TranscriptSegment{
+		{Start: 0, Duration: 2.5, Text: "hello world"},
+		{Start: 2.51, Duration: 5 - 2.51, Text: "rock & roll"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseVTT = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseVTTManualCaptions(t *testing.T) {
+	raw := "WEBVTT\r\n\r\nNOTE a comment\r\n\r\n1\r\n01:02:03.500 --> 01:02:05.000\r\n<i>First</i> line\r\nsecond line\r\n\r\n2\r\n01:02:05.000 --> 01:02:06.000\r\nlast\r\n"
+	got := parseVTT(raw)
+	want := []TranscriptSegment{
+		{Start: 3723.5, Duration: 1.5, Text: "First line"},
+		{Start: 3723.5, Duration: 1.5, Text: "second line"},
+		{Start: 3725, Duration: 1, Text: "last"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseVTT = %+v, want %+v", got, want)
+	}
+}
+
+type brokenCaptionProvider struct{}
+
+func (brokenCaptionProvider) Name() string { return "caption api" }
+
+func (brokenCaptionProvider) FetchTranscript(ctx context.Context, videoID string, languages []string, track string) (Transcript, error) {
+	return Transcript{}, errors.New("failed to extract video details: unexpected page layout")
+}
+
+// fakeYTDLP writes a script that behaves like yt-dlp for a video that only
+// has auto-generated English captions.
+func fakeYTDLP(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the yt-dlp stand-in is a shell script")
+	}
+	script := `#!/bin/sh
+out=""
+auto=""
+while [ $# -gt 0 ]; do
+	case "$1" in
+	-o) out="$2"; shift ;;
+	--write-auto-subs) auto=1 ;;
+	esac
+	shift
+done
+[ -n "$auto" ] || exit 0
+cat > "$(echo "$out" | sed 's/%(ext)s/en.vtt/')" <<'VTT'
+` + autoCaptionsVTT + `VTT
+`
+	path := filepath.Join(t.TempDir(), "yt-dlp")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFetchTranscriptFallsBackWhenCaptionClientBreaks(t *testing.T) {
+	subtitles := &YTDLPSubtitleProvider{}
+	subtitles.Configure(true, fakeYTDLP(t))
+	service := &TranscriptService{Providers: []TranscriptProvider{brokenCaptionProvider{}, subtitles}}
+
+	transcript, err := service.FetchTranscript(context.Background(), "dQw4w9WgXcQ", []string{"en"}, TranscriptTrackPreferManual)
+	if err != nil {
+		t.Fatalf("FetchTranscript: %v", err)
+	}
+	if transcript.Source != TranscriptSourceYTDLP || transcript.Track != TranscriptTrackGenerated || transcript.Language != "en" {
+		t.Fatalf("transcript from %s/%s/%s, want yt-dlp generated en", transcript.Source, transcript.Track, transcript.Language)
+	}
+	if transcript.Text != "hello world\nrock & roll" {
+		t.Fatalf("text = %q", transcript.Text)
+	}
+
+	// Manual captions only: yt-dlp finds none, and with the caption client
+	// broken that is no proof the video lacks captions.
+	_, err = service.FetchTranscript(context.Background(), "dQw4w9WgXcQ", []string{"en"}, TranscriptTrackManual)
+	var chain TranscriptChainError
+	if !errors.As(err, &chain) || len(chain) != 2 {
+		t.Fatalf("err = %v, want a chain error from both providers", err)
+	}
+	if IsNoTranscript(err) {
+		t.Fatal("a broken caption client counted as a video without captions")
+	}
+}
+
+func TestFetchTranscriptSkipsDisabledProviders(t *testing.T) {
+	service := &TranscriptService{Providers: []TranscriptProvider{brokenCaptionProvider{}, &YTDLPSubtitleProvider{}}}
+	_, err := service.FetchTranscript(context.Background(), "dQw4w9WgXcQ", []string{"en"}, TranscriptTrackPreferManual)
+	var chain TranscriptChainError
+	if !errors.As(err, &chain) || len(chain) != 1 || chain[0].Provider != "caption api" {
+		t.Fatalf("err = %v, want only the caption api error", err)
+	}
+}
@@ -11,6 +11,17 @@ export const AppService = {
   GetSyncSettings: () => call("AppService.GetSyncSettings"),
   UpdateSyncSettings: (input: any) => call("AppService.UpdateSyncSettings", input),
   SyncAllChannels: () => call("AppService.SyncAllChannels"),
+  ListSyncRuns: (limit: number) => call("AppService.ListSyncRuns", limit),
+  GetWebSubStatus: () => call("AppService.GetWebSubStatus"),
+  ListWebSubSubscriptions: () => call("AppService.ListWebSubSubscriptions"),
+  RenewWebSubLeases: () => call("AppService.RenewWebSubLeases"),
+  StartWebSub: () => call("AppService.StartWebSub"),
+  BackfillChannel: (channelID: string, maxVideos: number) =>
+    call("AppService.BackfillChannel", channelID, maxVideos),
+  SyncDueChannels: () => call("AppService.SyncDueChannels"),
+  ListChannelSchedules: () => call("AppService.ListChannelSchedules"),
+  SetChannelPollInterval: (channelID: string, minutes: number) =>
+    call("AppService.SetChannelPollInterval", channelID, minutes),
   SummarizeVideo: (
     videoID: string,
     templateName: string,
@@ -32,11 +43,54 @@ export const AppService = {
     ),
   ListChannels: () => call("AppService.ListChannels"),
   SyncChannelFeed: (channelID: string) => call("AppService.SyncChannelFeed", channelID),
+  ResolveChannelID: (input: string) => call("AppService.ResolveChannelID", input),
+  SubscribeFeed: (sourceType: string, input: string) => call("AppService.SubscribeFeed", sourceType, input),
   DeleteChannel: (channelID: string) => call("AppService.DeleteChannel", channelID),
+  RefreshChannelMetadata: (channelID: string) => call("AppService.RefreshChannelMetadata", channelID),
+  SetChannelPaused: (channelID: string, paused: boolean) => call("AppService.SetChannelPaused", channelID, paused),
+  SetChannelMuted: (channelID: string, muted: boolean) => call("AppService.SetChannelMuted", channelID, muted),
+  SetChannelSummaryExcludeTypes: (channelID: string, videoTypes: string[]) =>
+    call("AppService.SetChannelSummaryExcludeTypes", channelID, videoTypes),
+  ListVideoRevisions: (videoID: string) => call("AppService.ListVideoRevisions", videoID),
+  ListTranscriptSegments: (videoID: string) => call("AppService.ListTranscriptSegments", videoID),
+  SetChannelTranscriptPreferences: (channelID: string, languages: string[], track: string) =>
+    call("AppService.SetChannelTranscriptPreferences", channelID, languages, track),
+  SetVideoTranscriptPreferences: (videoID: string, languages: string[], track: string) =>
+    call("AppService.SetVideoTranscriptPreferences", videoID, languages, track),
+  RetryTranscript: (videoID: string) => call("AppService.RetryTranscript", videoID),
+  GetTranscriptFetchStatus: () => call("AppService.GetTranscriptFetchStatus"),
+  ResumeTranscriptFetches: () => call("AppService.ResumeTranscriptFetches"),
+  ListVideoStats: (videoID: string) => call("AppService.ListVideoStats", videoID),
+  EnrichVideo: (videoID: string) => call("AppService.EnrichVideo", videoID),
+  ImportOPML: (path: string) => call("AppService.ImportOPML", path),
+  ExportOPML: () => call("AppService.ExportOPML"),
+  ImportSubscriptions: (path: string, format: string, syncNew: boolean) =>
+    call("AppService.ImportSubscriptions", path, format, syncNew),
+  GetImportProgress: () => call("AppService.GetImportProgress"),
+  ListPlaylists: () => call("AppService.ListPlaylists"),
+  SyncPlaylistFeed: (playlistID: string) => call("AppService.SyncPlaylistFeed", playlistID),
+  DeletePlaylist: (playlistID: string) => call("AppService.DeletePlaylist", playlistID),
+  ListPlaylistVideos: (playlistID: string) => call("AppService.ListPlaylistVideos", playlistID),
   ListTemplates: () => call("AppService.ListTemplates"),
   SaveTemplate: (input: any) => call("AppService.SaveTemplate", input),
   DeleteTemplate: (name: string) => call("AppService.DeleteTemplate", name),
   ResetDefaultTemplates: () => call("AppService.ResetDefaultTemplates"),
+  ListChannelGroups: () => call("AppService.ListChannelGroups"),
+  SaveChannelGroup: (input: any) => call("AppService.SaveChannelGroup", input),
+  DeleteChannelGroup: (id: number) => call("AppService.DeleteChannelGroup", id),
+  ReorderChannelGroups: (groupIDs: number[]) => call("AppService.ReorderChannelGroups", groupIDs),
+  AddChannelToGroup: (groupID: number, channelID: string) => call("AppService.AddChannelToGroup", groupID, channelID),
+  RemoveChannelFromGroup: (groupID: number, channelID: string) =>
+    call("AppService.RemoveChannelFromGroup", groupID, channelID),
+  ReorderGroupChannels: (groupID: number, channelIDs: string[]) =>
+    call("AppService.ReorderGroupChannels", groupID, channelIDs),
+  ListGroupChannels: (groupID: number) => call("AppService.ListGroupChannels", groupID),
+  SyncChannelGroup: (groupID: number) => call("AppService.SyncChannelGroup", groupID),
+  ListRules: () => call("AppService.ListRules"),
+  SaveRule: (input: any) => call("AppService.SaveRule", input),
+  DeleteRule: (id: number) => call("AppService.DeleteRule", id),
+  ReorderRules: (ruleIDs: number[]) => call("AppService.ReorderRules", ruleIDs),
+  DryRunRule: (input: any, limit: number) => call("AppService.DryRunRule", input, limit),
   ListCollections: () => call("AppService.ListCollections"),
   CreateCollection: (input: any) => call("AppService.CreateCollection", input),
   DeleteCollection: (id: number) => call("AppService.DeleteCollection", id),
@@ -10,7 +10,7 @@ import (
 	"runtime"
 	"time"
 
-	"ytfeedgenerator/backend/app"
+	feedapp "ytfeedgenerator/backend/app"
 	"ytfeedgenerator/backend/services"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
@@ -75,7 +75,7 @@ func main() {
 	// 'Bind' is a list of Go struct instances. The frontend has access to the methods of these instances.
 	// 'Mac' options tailor the application when running an macOS.
 	notifier := notifications.New()
-	appService, err := app.NewAppService(getDBPath("ytfeed.db"))
+	appService, err := feedapp.NewAppService(getDBPath("ytfeed.db"))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -89,7 +89,8 @@ func main() {
 			application.NewService(notifier),
 		},
 		Assets: application.AssetOptions{
-			Handler: application.AssetFileServerFS(assets),
+			Handler:    application.AssetFileServerFS(assets),
+			Middleware: appService.Thumbnails.Middleware,
 		},
 		Mac: application.MacOptions{
 			ApplicationShouldTerminateAfterLastWindowClosed: false,
@@ -133,12 +134,12 @@ func main() {
 
 	syncItem.OnClick(func(_ *application.Context) {
 		go func() {
-			summary, err := appService.SyncAllChannels()
+			summary, err := appService.SyncAllChannelsFrom(feedapp.SyncTriggerTray)
 			if err != nil {
 				_ = appService.Notification.Notify(nil, "Sync failed", err.Error())
 				return
 			}
-			msg := fmt.Sprintf("New: %d, Updated: %d", summary.TotalNew, summary.TotalUpdated)
+			msg := fmt.Sprintf("New: %d, Updated: %d, Failed: %d", summary.TotalNew, summary.TotalUpdated, summary.TotalFailed)
 			_ = appService.Notification.Notify(nil, "Sync complete", msg)
 		}()
 	})
@@ -169,13 +170,11 @@ func main() {
 				time.Sleep(10 * time.Second)
 				continue
 			}
-			interval := time.Duration(settings.IntervalMinutes) * time.Minute
-			if interval < time.Minute {
-				interval = time.Minute
-			}
-			time.Sleep(interval)
+			// Each channel keeps its own next poll time; wake up often and
+			// sync whatever is due.
+			time.Sleep(time.Minute)
 
-			summary, err := appService.SyncAllChannels()
+			summary, err := appService.SyncDueChannels()
 			if err != nil {
 				if settings.NotificationsEnabled {
 					_ = appService.Notification.Notify(nil, "Sync failed", err.Error())
@@ -184,11 +183,27 @@ func main() {
 			}
 			if settings.NotificationsEnabled && summary.TotalNew > 0 {
 				msg := fmt.Sprintf("New: %d, Updated: %d", summary.TotalNew, summary.TotalUpdated)
+				if summary.TotalFailed > 0 {
+					msg += fmt.Sprintf(", Failed: %d", summary.TotalFailed)
+				}
 				_ = appService.Notification.Notify(nil, "New videos detected", msg)
 			}
 		}
 	}()
 
+	// WebSub pushes new uploads as they happen; polling stays as the fallback.
+	if err := appService.StartWebSub(); err != nil {
+		log.Printf("websub: %v", err)
+	}
+	go func() {
+		for {
+			if _, err := appService.RenewWebSubLeases(); err != nil {
+				log.Printf("websub renew: %v", err)
+			}
+			time.Sleep(time.Hour)
+		}
+	}()
+
 	go func() {
 		for {
 			settings, err := appService.GetAppSettings()